@@ -0,0 +1,195 @@
+package gx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule identifies an accessibility check of CheckAccessibility.
+type Rule string
+
+const (
+	// RuleImageAlt requires images to have an alt attribute, empty for
+	// decorative images.
+	RuleImageAlt Rule = "image-alt"
+	// RuleLabel requires form controls to have a label, either a <label>
+	// around them or pointing at their id, or an aria-label.
+	RuleLabel Rule = "label"
+	// RuleButtonName requires buttons to have text or an aria-label.
+	RuleButtonName Rule = "button-name"
+	// RuleHeadingOrder requires heading levels to increase one at a time.
+	RuleHeadingOrder Rule = "heading-order"
+	// RuleHTMLLang requires the <html> element to have a lang attribute.
+	RuleHTMLLang Rule = "html-lang"
+	// RuleRole requires role attributes to be ARIA roles.
+	RuleRole Rule = "role"
+)
+
+// Finding is an accessibility issue found by CheckAccessibility.
+type Finding struct {
+	Rule Rule
+	// Path is the element with the issue, as its ancestors and itself, e.g.
+	// "html > body > form#login > input".
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Path, f.Message, f.Rule)
+}
+
+// CheckAccessibility renders node with c and returns the accessibility issues
+// found: images without alt text, form controls without label, buttons
+// without name, skipped heading levels, a missing lang on <html> and invalid
+// roles. Like Validate, it is meant for tests and development.
+func CheckAccessibility(c *Context, node Node) ([]Finding, error) {
+	l := &accessibilityChecker{labels: make(map[string]bool)}
+	if err := observe(c, node, l); err != nil {
+		return nil, err
+	}
+	for _, control := range l.controls {
+		if !l.labels[control.id] {
+			l.findings = append(l.findings, control.finding)
+		}
+	}
+	return l.findings, nil
+}
+
+// ariaRoles are the roles defined by WAI-ARIA.
+var ariaRoles = tags("alert", "alertdialog", "application", "article", "banner", "blockquote", "button",
+	"caption", "cell", "checkbox", "code", "columnheader", "combobox", "complementary", "contentinfo",
+	"definition", "deletion", "dialog", "document", "emphasis", "feed", "figure", "form", "generic", "grid",
+	"gridcell", "group", "heading", "img", "insertion", "link", "list", "listbox", "listitem", "log", "main",
+	"mark", "marquee", "math", "menu", "menubar", "menuitem", "menuitemcheckbox", "menuitemradio", "meter",
+	"navigation", "none", "note", "option", "paragraph", "presentation", "progressbar", "radio", "radiogroup",
+	"region", "row", "rowgroup", "rowheader", "scrollbar", "search", "searchbox", "separator", "slider",
+	"spinbutton", "status", "strong", "subscript", "superscript", "switch", "tab", "table", "tablist",
+	"tabpanel", "term", "textbox", "time", "timer", "toolbar", "tooltip", "tree", "treegrid", "treeitem")
+
+// unlabeledInputs are the input types that need no label.
+var unlabeledInputs = tags("hidden", "submit", "reset", "button", "image")
+
+// headingLevels maps the heading elements to their level.
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// pendingControl is a form control that is fine if a label for its id is
+// rendered.
+type pendingControl struct {
+	id      string
+	finding Finding
+}
+
+type accessibilityChecker struct {
+	path elementPath
+	// named tells, for each element of path, whether it has an accessible
+	// name so far.
+	named []bool
+	// labels are the ids that labels are for.
+	labels   map[string]bool
+	controls []pendingControl
+	heading  int
+	findings []Finding
+}
+
+func (l *accessibilityChecker) report(rule Rule, format string, args ...any) {
+	l.findings = append(l.findings, Finding{rule, l.path.String(), fmt.Sprintf(format, args...)})
+}
+
+func (l *accessibilityChecker) open(e *Element) {
+	l.path = append(l.path, e)
+	l.named = append(l.named, hasAriaName(e))
+
+	if role, ok := e.attr("role"); ok {
+		for _, name := range strings.Fields(role) {
+			if !ariaRoles[name] {
+				l.report(RuleRole, "%q is not an ARIA role", name)
+			}
+		}
+	}
+	if lang, _ := e.attr("lang"); e.tag == "html" && strings.TrimSpace(lang) == "" {
+		l.report(RuleHTMLLang, "<html> has no lang attribute")
+	}
+	if level, ok := headingLevels[e.tag]; ok {
+		if l.heading > 0 && level > l.heading+1 {
+			l.report(RuleHeadingOrder, "<%s> follows <h%d>, skipping a level", e.tag, l.heading)
+		}
+		l.heading = level
+	}
+
+	switch e.tag {
+	case "img":
+		if alt, ok := e.attr("alt"); !ok {
+			l.report(RuleImageAlt, "<img> has no alt attribute, use an empty one if it is decorative")
+		} else if alt != "" {
+			l.nameAncestors()
+		}
+	case "input":
+		switch inputType, _ := e.attr("type"); inputType {
+		case "button":
+			if value, _ := e.attr("value"); value == "" && !l.named[len(l.named)-1] {
+				l.report(RuleButtonName, "button input has no value or aria-label")
+			}
+		case "image":
+			if alt, _ := e.attr("alt"); alt == "" && !l.named[len(l.named)-1] {
+				l.report(RuleImageAlt, "image input has no alt attribute")
+			}
+		default:
+			if !unlabeledInputs[inputType] {
+				l.checkLabel(e)
+			}
+		}
+	case "select", "textarea":
+		l.checkLabel(e)
+	}
+}
+
+// hasAriaName reports whether the element is named by its attributes.
+func hasAriaName(e *Element) bool {
+	for _, key := range [...]string{"aria-label", "aria-labelledby", "title"} {
+		if value, _ := e.attr(key); strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLabel reports the current form control unless it is labelled. A
+// control with an id may be labelled by a label rendered later.
+func (l *accessibilityChecker) checkLabel(e *Element) {
+	if l.named[len(l.named)-1] || l.path.inside("label") {
+		return
+	}
+	finding := Finding{RuleLabel, l.path.String(), fmt.Sprintf("<%s> has no label or aria-label", e.tag)}
+	if id, _ := e.attr("id"); id != "" {
+		l.controls = append(l.controls, pendingControl{id, finding})
+		return
+	}
+	l.findings = append(l.findings, finding)
+}
+
+// nameAncestors records that the open elements have content naming them.
+func (l *accessibilityChecker) nameAncestors() {
+	for i := range l.named {
+		l.named[i] = true
+	}
+}
+
+func (l *accessibilityChecker) close(e *Element) {
+	last := len(l.path) - 1
+	if e.tag == "button" && !l.named[last] {
+		l.report(RuleButtonName, "<button> has no text or aria-label")
+	}
+	if e.tag == "label" {
+		if id, _ := e.attr("for"); id != "" {
+			l.labels[id] = true
+		}
+	}
+	l.path = l.path[:last]
+	l.named = l.named[:last]
+}
+
+func (l *accessibilityChecker) text(s string) {
+	if strings.TrimSpace(s) != "" {
+		l.nameAncestors()
+	}
+}
@@ -0,0 +1,68 @@
+package gx_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestCheckAccessibility(t *testing.T) {
+	node := gx.Html(
+		gx.Body(
+			gx.H1(gx.Text("Title")),
+			gx.H3(gx.Text("Skipped")),
+			gx.Img(gx.Src("/logo.png")),
+			gx.Img(gx.Src("/spacer.png"), gx.Attr("alt", "")),
+			gx.Div(gx.Role("navigation menu")),
+			gx.Div(gx.Role("nav")),
+			gx.Form(
+				gx.ID("login"),
+				gx.Input(gx.Type("text"), gx.Name("unlabeled")),
+				gx.Input(gx.Type("text"), gx.ID("email")),
+				gx.Label(gx.For("email"), gx.Text("Email")),
+				gx.Label(gx.Text("Password"), gx.Input(gx.Type("password"))),
+				gx.Select(gx.ID("orphan")),
+				gx.Textarea(gx.AriaLabel("Comment")),
+				gx.Input(gx.Type("hidden"), gx.Name("token")),
+				gx.Input(gx.Type("button")),
+				gx.Button(gx.Span(gx.Text(" "))),
+				gx.Button(gx.Img(gx.Src("/icon.png"), gx.Attr("alt", "Save"))),
+				gx.Button(gx.AriaLabel("Close"), gx.Text("")),
+				gx.Button(gx.Text("Submit")),
+			),
+		),
+	)
+
+	findings, err := gx.CheckAccessibility(gx.NewContext(), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []gx.Finding{
+		{gx.RuleHTMLLang, "html", "<html> has no lang attribute"},
+		{gx.RuleHeadingOrder, "html > body > h3", "<h3> follows <h1>, skipping a level"},
+		{gx.RuleImageAlt, "html > body > img", "<img> has no alt attribute, use an empty one if it is decorative"},
+		{gx.RuleRole, "html > body > div", `"nav" is not an ARIA role`},
+		{gx.RuleLabel, "html > body > form#login > input", "<input> has no label or aria-label"},
+		{gx.RuleButtonName, "html > body > form#login > input", "button input has no value or aria-label"},
+		{gx.RuleButtonName, "html > body > form#login > button", "<button> has no text or aria-label"},
+		{gx.RuleLabel, "html > body > form#login > select#orphan", "<select> has no label or aria-label"},
+	}
+	if !slices.Equal(findings, expected) {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, findings)
+	}
+}
+
+func TestCheckAccessibilityValid(t *testing.T) {
+	ctx := gx.NewContext()
+	ctx.Push("John")
+
+	findings, err := gx.CheckAccessibility(ctx, page())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
@@ -1,144 +1,379 @@
 package gx
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
 )
 
 type attrNode struct {
 	key   string
 	value string
+	// trusted skips the URLPolicy check for URL attributes.
+	trusted bool
+	// boolean attributes are written without a value.
+	boolean bool
+	// err is returned instead of writing the attribute.
+	err error
 }
 
+// Render writes the attribute. Attributes rendered as the content of an
+// element were not found among its children, e.g. when returned by a
+// WithContext component, which is an error in strict mode.
 func (a *attrNode) Render(c *Context, w io.Writer) error {
-	_, err := fmt.Fprintf(w, ` %s="%s"`, a.key, a.value)
+	if c.strict && c.tag != "" {
+		return fmt.Errorf("%w: attribute %q in the content of <%s>", ErrNotAttribute, a.key, c.tag)
+	}
+	return writeAttr(c, w, a)
+}
+
+func writeAttr(c *Context, w io.Writer, a *attrNode) error {
+	if a.err != nil {
+		return a.err
+	}
+	if a.boolean {
+		return writeStrings(w, " ", a.key)
+	}
+	value := a.value
+	if checkedURLAttr(a.key) && !a.trusted && !c.urlPolicy.allowsAttr(a.key, value) {
+		value = unsafeURL
+	}
+	if c.minify != nil {
+		if written, err := writeMinifiedAttr(w, a.key, value); written || err != nil {
+			return err
+		}
+	}
+
+	if err := writeStrings(w, " ", a.key, `="`); err != nil {
+		return err
+	}
+	if err := escapeAttrValue(w, a.key, value); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, `"`)
 	return err
 }
 
+// attrs returns the attributes among nodes, with their position, including
+// those in fragments, chosen conditionals and attribute groups.
+func attrs(nodes []Node) iter.Seq2[int, *attrNode] {
+	return func(yield func(int, *attrNode) bool) {
+		i := 0
+		walkAttrs(nodes, &i, yield)
+	}
+}
+
+func walkAttrs(nodes []Node, i *int, yield func(int, *attrNode) bool) bool {
+	for _, node := range nodes {
+		if !walkAttr(node, i, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func walkAttr(node Node, i *int, yield func(int, *attrNode) bool) bool {
+	switch n := node.(type) {
+	case *attrNode:
+		*i++
+		return yield(*i-1, n)
+	case *fragmentNode:
+		return walkAttrs(n.children, i, yield)
+	case *attrGroupNode:
+		return walkAttrs(n.attrs, i, yield)
+	case *ifNode:
+		if child := n.chosen(); child != nil {
+			return walkAttr(child, i, yield)
+		}
+	}
+	return true
+}
+
+// hasAttrs reports whether node renders attributes.
+func hasAttrs(node Node) bool {
+	for range attrs([]Node{node}) {
+		return true
+	}
+	return false
+}
+
+// writeClassAttr writes the class attributes among nodes as a single one,
+// without repeating class names.
+func writeClassAttr(w io.Writer, nodes []Node) error {
+	if _, err := io.WriteString(w, ` class="`); err != nil {
+		return err
+	}
+	var buf [16]string
+	written := buf[:0]
+	for _, attr := range attrs(nodes) {
+		if attr.key != "class" {
+			continue
+		}
+		for rest := attr.value; ; {
+			var name string
+			name, rest = nextClass(rest)
+			if name == "" {
+				break
+			}
+			if slices.Contains(written, name) {
+				continue
+			}
+			if len(written) > 0 {
+				if _, err := io.WriteString(w, " "); err != nil {
+					return err
+				}
+			}
+			if err := writeEscaped(w, name, htmlReplacement); err != nil {
+				return err
+			}
+			written = append(written, name)
+		}
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
+
+// nextClass returns the first class name of list and what follows it.
+func nextClass(list string) (name, rest string) {
+	list = strings.TrimLeft(list, htmlSpace)
+	end := strings.IndexAny(list, htmlSpace)
+	if end < 0 {
+		return list, ""
+	}
+	return list[:end], list[end:]
+}
+
+const htmlSpace = " \t\n\f\r"
+
+// writeStyleAttr writes the style attributes among nodes as a single one,
+// separating their declarations with semicolons.
+func writeStyleAttr(w io.Writer, nodes []Node) error {
+	if _, err := io.WriteString(w, ` style="`); err != nil {
+		return err
+	}
+	// pending is written once it is known whether another declaration
+	// follows it.
+	pending := ""
+	for _, attr := range attrs(nodes) {
+		if attr.key != "style" {
+			continue
+		}
+		style := strings.TrimSpace(attr.value)
+		if style == "" {
+			continue
+		}
+		if pending != "" {
+			if err := writeEscaped(w, strings.TrimRight(pending, ";"), htmlReplacement); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "; "); err != nil {
+				return err
+			}
+		}
+		pending = style
+	}
+	if err := writeEscaped(w, pending, htmlReplacement); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
+
+type attrGroupNode struct {
+	attrs []Node
+}
+
+// Render writes the attributes of the group, see attrNode.Render.
+func (g *attrGroupNode) Render(c *Context, w io.Writer) error {
+	for _, attr := range attrs(g.attrs) {
+		if err := attr.Render(c, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AttrGroup groups attributes so that they can be returned from a function,
+// included with If or given to an element at once. Other nodes are ignored.
+func AttrGroup(attrs ...Node) Node {
+	return &attrGroupNode{attrs}
+}
+
+// Attrs adds an attribute for each entry of attrs, in the order of their
+// names.
+func Attrs(attrs map[string]string) Node {
+	group := &attrGroupNode{make([]Node, 0, len(attrs))}
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		group.attrs = append(group.attrs, &attrNode{key: key, value: attrs[key]})
+	}
+	return group
+}
+
 func Type(t string) Node {
-	return &attrNode{"type", t}
+	return &attrNode{key: "type", value: t}
 }
 
 func Rel(url string) Node {
-	return &attrNode{"rel", url}
+	return &attrNode{key: "rel", value: url}
 }
 
 func Href(url string) Node {
-	return &attrNode{"href", url}
+	return &attrNode{key: "href", value: url}
 }
 
 func Class(class string) Node {
-	return &attrNode{"class", class}
+	return &attrNode{key: "class", value: class}
+}
+
+// Classes adds the class names whose condition is true, in alphabetical
+// order.
+func Classes(classes map[string]bool) Node {
+	var names []string
+	for name, enabled := range classes {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return Fragment()
+	}
+	slices.Sort(names)
+	return &attrNode{key: "class", value: strings.Join(names, " ")}
 }
 
 func ID(id string) Node {
-	return &attrNode{"id", id}
+	return &attrNode{key: "id", value: id}
 }
 
 func Attr(attr, value string) Node {
-	return &attrNode{attr, value}
+	return &attrNode{key: attr, value: value}
+}
+
+// BoolAttr adds the boolean attribute attr, written without a value, unless
+// one of the given conditions is false.
+func BoolAttr(attr string, on ...bool) Node {
+	for _, enabled := range on {
+		if !enabled {
+			return Fragment()
+		}
+	}
+	return &attrNode{key: attr, boolean: true}
+}
+
+// JSONAttr adds the attribute attr with value encoded as JSON. An encoding
+// error is returned when the element is rendered.
+func JSONAttr(attr string, value any) Node {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return &attrNode{key: attr, err: fmt.Errorf("gx: encoding %s: %w", attr, err)}
+	}
+	return &attrNode{key: attr, value: string(data)}
 }
 
 func Name(name string) Node {
-	return &attrNode{"name", name}
+	return &attrNode{key: "name", value: name}
 }
 
 func Src(url string) Node {
-	return &attrNode{"src", url}
+	return &attrNode{key: "src", value: url}
 }
 
 func Placeholder(text string) Node {
-	return &attrNode{"placeholder", text}
+	return &attrNode{key: "placeholder", value: text}
 }
 
 func Min(value string) Node {
-	return &attrNode{"min", value}
+	return &attrNode{key: "min", value: value}
 }
 
 func Max(value string) Node {
-	return &attrNode{"max", value}
+	return &attrNode{key: "max", value: value}
 }
 
 func Data(key, value string) Node {
-	return &attrNode{"data-" + key, value}
+	return &attrNode{key: "data-" + key, value: value}
 }
 
 func For(id string) Node {
-	return &attrNode{"for", id}
+	return &attrNode{key: "for", value: id}
 }
 
 func Action(url string) Node {
-	return &attrNode{"action", url}
+	return &attrNode{key: "action", value: url}
 }
 
 func Method(method string) Node {
-	return &attrNode{"method", method}
+	return &attrNode{key: "method", value: method}
 }
 
 func Target(target string) Node {
-	return &attrNode{"target", target}
+	return &attrNode{key: "target", value: target}
 }
 
 func Title_(title string) Node {
-	return &attrNode{"title", title}
+	return &attrNode{key: "title", value: title}
 }
 
 func Style_(style string) Node {
-	return &attrNode{"style", style}
+	return &attrNode{key: "style", value: style}
 }
 
 func Lang(lang string) Node {
-	return &attrNode{"lang", lang}
+	return &attrNode{key: "lang", value: lang}
 }
 
 func Dir(direction string) Node {
-	return &attrNode{"dir", direction}
+	return &attrNode{key: "dir", value: direction}
 }
 
 func TabIndex(index string) Node {
-	return &attrNode{"tabindex", index}
+	return &attrNode{key: "tabindex", value: index}
 }
 
 func Role(role string) Node {
-	return &attrNode{"role", role}
+	return &attrNode{key: "role", value: role}
 }
 
 func AriaLabel(label string) Node {
-	return &attrNode{"aria-label", label}
+	return &attrNode{key: "aria-label", value: label}
 }
 
 func AriaHidden() Node {
-	return &attrNode{"aria-hidden", "true"}
+	return &attrNode{key: "aria-hidden", value: "true"}
 }
 
-func Disabled() Node {
-	return &attrNode{"disabled", "disabled"}
+func Disabled(on ...bool) Node {
+	return BoolAttr("disabled", on...)
 }
 
-func Required() Node {
-	return &attrNode{"required", "required"}
+func Required(on ...bool) Node {
+	return BoolAttr("required", on...)
 }
 
-func Readonly() Node {
-	return &attrNode{"readonly", "readonly"}
+func Readonly(on ...bool) Node {
+	return BoolAttr("readonly", on...)
 }
 
-func Multiple() Node {
-	return &attrNode{"multiple", "multiple"}
+func Multiple(on ...bool) Node {
+	return BoolAttr("multiple", on...)
 }
 
-func Checked() Node {
-	return &attrNode{"checked", "checked"}
+func Checked(on ...bool) Node {
+	return BoolAttr("checked", on...)
 }
 
-func Autofocus() Node {
-	return &attrNode{"autofocus", "autofocus"}
+func Autofocus(on ...bool) Node {
+	return BoolAttr("autofocus", on...)
 }
 
-func Hidden() Node {
-	return &attrNode{"hidden", "hidden"}
+func Hidden(on ...bool) Node {
+	return BoolAttr("hidden", on...)
 }
 
-func Selected() Node {
-	return &attrNode{"selected", "selected"}
+func Selected(on ...bool) Node {
+	return BoolAttr("selected", on...)
 }
@@ -0,0 +1,278 @@
+package gx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultSlot is the name of the slot created by Slot and filled by
+// CompiledTemplate.Render.
+const DefaultSlot = "default"
+
+var (
+	ErrUnknownSlot   = errors.New("gx: unknown slot")
+	ErrMissingSlot   = errors.New("gx: missing slot")
+	ErrDuplicateSlot = errors.New("gx: duplicate slot")
+	ErrAmbiguousSlot = errors.New("gx: ambiguous slot")
+	// ErrSlotInComponent is returned when rendering a compiled template with
+	// a slot inside one of its WithContext components, which Compile does not
+	// render.
+	ErrSlotInComponent = errors.New("gx: slot inside a WithContext component of a compiled template")
+)
+
+type slotNode struct {
+	name string
+}
+
+// Render marks the position of the slot when compiling. A slot rendered inside
+// a WithContext component of a compiled template fails with
+// ErrSlotInComponent, and one rendered anywhere else outside of Compile fails
+// with ErrAmbiguousSlot, since neither has content to render.
+func (s *slotNode) Render(c *Context, w io.Writer) error {
+	if component, ok := c.hole.(*componentNode); ok && c.compiler == nil {
+		return fmt.Errorf("%w: slot %q in %s", ErrSlotInComponent, s.name, component.name())
+	}
+	if c.compiler == nil {
+		return fmt.Errorf("%w %q: rendered outside of Compile", ErrAmbiguousSlot, s.name)
+	}
+	return c.compiler.slot(c, s.name)
+}
+
+func Slot() Node {
+	return &slotNode{DefaultSlot}
+}
+
+// NamedSlot marks where the content of the slot called name goes in a
+// compiled template.
+func NamedSlot(name string) Node {
+	return &slotNode{name}
+}
+
+// Slots maps slot names to their content.
+type Slots map[string]Node
+
+type partKind int
+
+const (
+	staticPart partKind = iota
+	slotPart
+	// holePart is a WithContext component, rendered on each render of the
+	// template.
+	holePart
+	// providePart and restorePart surround the children of a Provide.
+	providePart
+	restorePart
+	// regionPart and regionEndPart surround the children of a Region.
+	regionPart
+	regionEndPart
+)
+
+type templatePart struct {
+	kind partKind
+	// text is the content of a static part or the name of a slot or region.
+	text string
+	node Node
+	// tag is the element enclosing a slot or a hole.
+	tag   string
+	key   any
+	value any
+}
+
+type CompiledTemplate struct {
+	parts []templatePart
+	slots []string
+}
+
+// Render fills the default slot with children.
+func (t *CompiledTemplate) Render(children ...Node) Node {
+	return t.RenderSlots(Slots{DefaultSlot: Fragment(children...)})
+}
+
+// RenderSlots fills each slot of the template by name. Rendering fails if a
+// slot of the template is not filled or if slots has a slot the template does
+// not have.
+func (t *CompiledTemplate) RenderSlots(slots Slots) Node {
+	return &compiledNode{
+		template: t,
+		slots:    slots,
+	}
+}
+
+type compiledNode struct {
+	template *CompiledTemplate
+	slots    Slots
+}
+
+type savedValue struct {
+	key    any
+	entry  contextValue
+	exists bool
+}
+
+func (cn *compiledNode) Render(c *Context, w io.Writer) error {
+	t := cn.template
+	for name := range cn.slots {
+		if !t.hasSlot(name) {
+			return fmt.Errorf("%w %q", ErrUnknownSlot, name)
+		}
+	}
+	for _, name := range t.slots {
+		if _, ok := cn.slots[name]; !ok {
+			return fmt.Errorf("%w %q", ErrMissingSlot, name)
+		}
+	}
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	parentTag := c.tag
+	var saved []savedValue
+	err := cn.renderParts(c, w, &saved)
+	for i := len(saved) - 1; i >= 0; i-- {
+		c.restore(saved[i].key, saved[i].entry, saved[i].exists)
+	}
+	c.tag = parentTag
+	return err
+}
+
+func (cn *compiledNode) renderParts(c *Context, w io.Writer, saved *[]savedValue) error {
+	// regionDepth counts the regions entered since the one wanted by a
+	// Partial, whose content is written to the writer of the Partial.
+	regionDepth := 0
+	for _, part := range cn.template.parts {
+		var err error
+		switch part.kind {
+		case staticPart:
+			_, err = io.WriteString(w, part.text)
+		case slotPart:
+			c.tag = part.tag
+			err = cn.slots[part.text].Render(c, w)
+		case holePart:
+			c.tag = part.tag
+			hole := c.hole
+			c.hole = part.node
+			err = part.node.Render(c, w)
+			c.hole = hole
+		case providePart:
+			entry, exists := c.swap(part.key, part.value)
+			*saved = append(*saved, savedValue{part.key, entry, exists})
+		case restorePart:
+			last := (*saved)[len(*saved)-1]
+			*saved = (*saved)[:len(*saved)-1]
+			c.restore(last.key, last.entry, last.exists)
+		case regionPart:
+			if regionDepth > 0 {
+				regionDepth++
+			} else if target := c.region; target != nil && target.name == part.text && !target.found {
+				target.found = true
+				regionDepth = 1
+				w = target.w
+			}
+		case regionEndPart:
+			if regionDepth > 0 {
+				regionDepth--
+				if regionDepth == 0 {
+					return errRegionDone
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CompiledTemplate) hasSlot(name string) bool {
+	for i := range t.slots {
+		if t.slots[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compiler splits the output of a template into static parts and the slots
+// and context-dependent nodes between them while it is rendered.
+type compiler struct {
+	buf      bytes.Buffer
+	template CompiledTemplate
+}
+
+// flush turns what was rendered since the last dynamic part into a static
+// part.
+func (cp *compiler) flush() {
+	if cp.buf.Len() == 0 {
+		return
+	}
+	cp.template.parts = append(cp.template.parts, templatePart{kind: staticPart, text: cp.buf.String()})
+	cp.buf.Reset()
+}
+
+func (cp *compiler) slot(c *Context, name string) error {
+	if cp.template.hasSlot(name) {
+		return fmt.Errorf("%w %q", ErrDuplicateSlot, name)
+	}
+	if rawTextElements[c.tag] {
+		return fmt.Errorf("%w %q: inside <%s>", ErrAmbiguousSlot, name, c.tag)
+	}
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: slotPart, text: name, tag: c.tag})
+	cp.template.slots = append(cp.template.slots, name)
+	return nil
+}
+
+func (cp *compiler) hole(c *Context, node Node) error {
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: holePart, node: node, tag: c.tag})
+	return nil
+}
+
+func (cp *compiler) provide(c *Context, p *provideNode) error {
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: providePart, key: p.key, value: p.value})
+	if err := renderChildren(c, &cp.buf, p.children); err != nil {
+		return err
+	}
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: restorePart})
+	return nil
+}
+
+func (cp *compiler) region(c *Context, r *regionNode) error {
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: regionPart, text: r.name})
+	if err := renderChildren(c, &cp.buf, r.children); err != nil {
+		return err
+	}
+	cp.flush()
+	cp.template.parts = append(cp.template.parts, templatePart{kind: regionEndPart})
+	return nil
+}
+
+// Compile pre-renders the static parts of template. Slots are filled and
+// WithContext components are rendered each time the compiled template is,
+// with the values provided around them.
+//
+// It fails if template has no slot, has several slots with the same name, or
+// has a slot inside a <script> or <style> element. Slots must not be inside
+// WithContext components, which are not rendered by Compile: rendering the
+// compiled template then fails with ErrSlotInComponent. The template is
+// rendered with a Context created with opts, e.g. WithStrict.
+func Compile(template Node, opts ...ContextOption) (*CompiledTemplate, error) {
+	cp := &compiler{}
+	ctx := NewContext(opts...)
+	ctx.compiler = cp
+
+	if err := template.Render(ctx, &cp.buf); err != nil {
+		return nil, err
+	}
+	if len(cp.template.slots) == 0 {
+		return nil, fmt.Errorf("%w: template has no slot", ErrMissingSlot)
+	}
+	cp.flush()
+
+	return &cp.template, nil
+}
@@ -0,0 +1,193 @@
+package gx_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func layout() gx.Node {
+	return gx.Html(
+		gx.Head(gx.Title(gx.NamedSlot("title"))),
+		gx.Body(
+			gx.Aside(gx.NamedSlot("sidebar")),
+			gx.Main(gx.Slot()),
+		),
+	)
+}
+
+func TestCompiledNamedSlots(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	compiled, err := gx.Compile(layout())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = compiled.RenderSlots(gx.Slots{
+		"title":        gx.Text("Home"),
+		"sidebar":      gx.Ul(gx.Li(gx.Text("link"))),
+		gx.DefaultSlot: gx.P(gx.Text("content")),
+	}).Render(ctx, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<html>
+		<head><title>Home</title></head>
+		<body>
+			<aside><ul><li>link</li></ul></aside>
+			<main><p>content</p></main>
+		</body>
+	</html>`
+	if buf.String() != normalizeHTML(expected) {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestCompiledUnknownSlot(t *testing.T) {
+	var buf strings.Builder
+
+	compiled, _ := gx.Compile(gx.Div(gx.Slot()))
+
+	err := compiled.RenderSlots(gx.Slots{
+		gx.DefaultSlot: gx.Text("content"),
+		"sidebar":      gx.Text("sidebar"),
+	}).Render(gx.NewContext(), &buf)
+	if !errors.Is(err, gx.ErrUnknownSlot) {
+		t.Errorf("expected %v, got %v", gx.ErrUnknownSlot, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", buf.String())
+	}
+}
+
+func TestCompiledMissingSlot(t *testing.T) {
+	var buf strings.Builder
+
+	compiled, _ := gx.Compile(layout())
+
+	err := compiled.Render(gx.Text("content")).Render(gx.NewContext(), &buf)
+	if !errors.Is(err, gx.ErrMissingSlot) {
+		t.Errorf("expected %v, got %v", gx.ErrMissingSlot, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", buf.String())
+	}
+}
+
+func TestCompileIgnoresSlotMarkupInContent(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	compiled, err := gx.Compile(gx.Div(
+		gx.Raw("<!-- slot -->"),
+		gx.Text("<!-- slot:default -->"),
+		gx.Slot(),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled.Render(gx.Text("content")).Render(ctx, &buf)
+
+	expected := `<div><!-- slot -->&lt;!-- slot:default --&gt;content</div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template gx.Node
+		err      error
+	}{
+		{"no slot", gx.Div(gx.Text("static")), gx.ErrMissingSlot},
+		{"duplicate slot", gx.Div(gx.Slot(), gx.Slot()), gx.ErrDuplicateSlot},
+		{"duplicate named slot", gx.Div(gx.NamedSlot("a"), gx.Slot(), gx.NamedSlot("a")), gx.ErrDuplicateSlot},
+		{"repeated slot", gx.Div(gx.Repeat(2, gx.Slot())), gx.ErrDuplicateSlot},
+		{"slot in script", gx.Script(gx.Slot()), gx.ErrAmbiguousSlot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gx.Compile(tt.template); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSlotOutsideCompile(t *testing.T) {
+	var buf strings.Builder
+
+	err := gx.Div(gx.Slot()).Render(gx.NewContext(), &buf)
+	if !errors.Is(err, gx.ErrAmbiguousSlot) {
+		t.Errorf("expected %v, got %v", gx.ErrAmbiguousSlot, err)
+	}
+}
+
+func TestCompiledDynamicParts(t *testing.T) {
+	type User struct {
+		Name string
+	}
+	type Theme string
+
+	header := gx.Header(gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Textf("%s (%s)", gx.Use[User](c).Name, gx.Use[Theme](c))
+	}))
+
+	compiled, err := gx.Compile(gx.Div(
+		gx.Provide(Theme("dark"), header),
+		gx.Main(gx.Slot()),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"John", "Jane"} {
+		ctx := gx.NewContext()
+		ctx.Push(User{name})
+		ctx.Push(Theme("light"))
+		var buf strings.Builder
+
+		if err := compiled.Render(gx.Text("content")).Render(ctx, &buf); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := `<div><header>` + name + ` (dark)</header><main>content</main></div>`
+		if buf.String() != expected {
+			t.Errorf("expected '%q', got '%q'", expected, buf.String())
+		}
+		if theme := gx.Use[Theme](ctx); theme != "light" {
+			t.Errorf("expected provided value to be restored, got %q", theme)
+		}
+	}
+}
+
+func TestCompiledSlotInsideDynamicPart(t *testing.T) {
+	var buf strings.Builder
+
+	compiled, err := gx.Compile(gx.Div(
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			return gx.Aside(gx.NamedSlot("sidebar"))
+		}),
+		gx.Slot(),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = compiled.Render(gx.Text("content")).Render(gx.NewContext(), &buf)
+	if !errors.Is(err, gx.ErrSlotInComponent) {
+		t.Fatalf("expected %v, got %v", gx.ErrSlotInComponent, err)
+	}
+	expected := `slot "sidebar" in github.com/bpingris/gx_test.TestCompiledSlotInsideDynamicPart.func1`
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to name the slot and the component, got %q", err)
+	}
+}
@@ -1,41 +1,187 @@
 package gx
 
 import (
+	"context"
 	"io"
 	"reflect"
+	"runtime"
 )
 
 type Context struct {
-	values map[reflect.Type]any
+	ctx context.Context
+	// values are keyed by their reflect.Type, or by the key of a Key.
+	values map[any]contextValue
+	// pushes counts the values set so far, to find the most recent one.
+	pushes int
+	// tag is the element currently being rendered, used to pick the escaping
+	// rules for text written inside it.
+	tag       string
+	urlPolicy URLPolicy
+	// compiler is set while a template is being compiled.
+	compiler *compiler
+	// hole is the dynamic part of a compiled template being rendered.
+	hole Node
+	// region is the region wanted by the Partial being rendered.
+	region *regionTarget
+	// layout is set when rendering indented HTML.
+	layout *indentLayout
+	// minify is set when rendering minified HTML.
+	minify *minifier
+	// strict makes structural mistakes fail rendering.
+	strict bool
+	// observer is notified of what is rendered by Validate.
+	observer observer
 }
 
-func NewContext() *Context {
-	return &Context{
-		values: make(map[reflect.Type]any),
+// ContextOption configures a Context created by NewContext.
+type ContextOption func(c *Context)
+
+// WithURLPolicy replaces DefaultURLPolicy for URL attributes rendered with the
+// context.
+func WithURLPolicy(policy URLPolicy) ContextOption {
+	return func(c *Context) {
+		c.urlPolicy = policy
+	}
+}
+
+func NewContext(opts ...ContextOption) *Context {
+	return NewContextFrom(context.Background(), opts...)
+}
+
+// NewContextFrom creates a Context bound to ctx. Rendering stops with the
+// error of ctx once it is done.
+func NewContextFrom(ctx context.Context, opts ...ContextOption) *Context {
+	c := &Context{
+		ctx:       ctx,
+		values:    make(map[any]contextValue),
+		urlPolicy: DefaultURLPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Context returns the context.Context the Context was created from, giving
+// components access to deadlines and request-scoped values.
+func (c *Context) Context() context.Context {
+	return c.ctx
+}
+
+type contextValue struct {
+	value any
+	order int
+}
+
+func (c *Context) set(key, value any) {
+	c.pushes++
+	c.values[key] = contextValue{value, c.pushes}
+}
+
+// swap sets the value of key and returns the value it replaces.
+func (c *Context) swap(key, value any) (contextValue, bool) {
+	prev, exists := c.values[key]
+	c.set(key, value)
+	return prev, exists
+}
+
+// restore undoes a swap.
+func (c *Context) restore(key any, prev contextValue, exists bool) {
+	if exists {
+		c.values[key] = prev
+	} else {
+		delete(c.values, key)
 	}
 }
 
 func (c *Context) Push(value any) {
-	typ := reflect.TypeOf(value)
-	c.values[typ] = value
+	c.set(reflect.TypeOf(value), value)
+}
+
+// PushAs is like Push but stores value under T, which is typically an
+// interface implemented by value.
+func PushAs[T any](c *Context, value T) {
+	c.set(reflect.TypeFor[T](), value)
 }
 
 func Use[T any](c *Context) T {
+	value, _ := SafeUse[T](c)
+	return value
+}
+
+// SafeUse returns the value of type T and whether it exists. When T is an
+// interface and no value was stored as T, the most recently set value that
+// implements T is returned.
+func SafeUse[T any](c *Context) (T, bool) {
+	typ := reflect.TypeFor[T]()
+	if entry, exists := c.values[typ]; exists {
+		// A nil interface is stored as nil and comes back as the zero value.
+		value, _ := entry.value.(T)
+		return value, true
+	}
+
 	var zero T
-	typ := reflect.TypeOf(zero)
-	if value, exists := c.values[typ]; exists {
-		return value.(T)
+	if typ.Kind() != reflect.Interface {
+		return zero, false
 	}
 
-	return zero
+	var found contextValue
+	for key, entry := range c.values {
+		if _, ok := key.(reflect.Type); !ok {
+			continue
+		}
+		if _, ok := entry.value.(T); ok && entry.order > found.order {
+			found = entry
+		}
+	}
+	if found.order == 0 {
+		return zero, false
+	}
+	return found.value.(T), true
 }
 
-func SafeUse[T any](c *Context) (T, bool) {
-	var zero T
-	typ := reflect.TypeOf(zero)
-	if value, exists := c.values[typ]; exists {
-		return value.(T), true
+// Key identifies a context value independently of its type, so that several
+// values of the same type can be provided at once.
+type Key[T any] struct {
+	key *contextKey
+}
+
+type contextKey struct {
+	name string
+}
+
+// NewKey creates a key, name is only used for debugging purposes.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{&contextKey{name}}
+}
+
+func (k Key[T]) String() string {
+	return k.key.name
+}
+
+// Push sets the value of the key in c.
+func (k Key[T]) Push(c *Context, value T) {
+	c.set(k.key, value)
+}
+
+// Provide is like the Provide function, for the value of the key.
+func (k Key[T]) Provide(value T, children ...Node) Node {
+	return &provideNode{k.key, value, children}
+}
+
+// Use returns the value of the key, or the zero value if it was not provided.
+func (k Key[T]) Use(c *Context) T {
+	value, _ := k.SafeUse(c)
+	return value
+}
+
+// SafeUse returns the value of the key and whether it was provided.
+func (k Key[T]) SafeUse(c *Context) (T, bool) {
+	if entry, exists := c.values[k.key]; exists {
+		value, _ := entry.value.(T)
+		return value, true
 	}
+	var zero T
 	return zero, false
 }
 
@@ -44,7 +190,21 @@ type componentNode struct {
 }
 
 func (n *componentNode) Render(c *Context, w io.Writer) error {
-	return n.fn(c).Render(c, w)
+	if c.compiler != nil {
+		return c.compiler.hole(c, n)
+	}
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if err := n.fn(c).Render(c, w); err != nil {
+		return n.wrapRenderError(err)
+	}
+	return nil
+}
+
+// name returns the name of the function of the component.
+func (n *componentNode) name() string {
+	return runtime.FuncForPC(reflect.ValueOf(n.fn).Pointer()).Name()
 }
 
 func WithContext(fn func(c *Context) Node) Node {
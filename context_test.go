@@ -1,6 +1,9 @@
 package gx_test
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/bpingris/gx"
@@ -62,3 +65,189 @@ func TestContextOverwriteValue(t *testing.T) {
 		t.Errorf("Expected 'second', got %q", result)
 	}
 }
+
+func TestContextKeys(t *testing.T) {
+	type User struct {
+		Name string
+	}
+	currentUser := gx.NewKey[User]("current user")
+	viewedUser := gx.NewKey[User]("viewed user")
+
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	profile := gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Textf("%s views %s", currentUser.Use(c).Name, viewedUser.Use(c).Name)
+	})
+
+	node := currentUser.Provide(User{"John"},
+		viewedUser.Provide(User{"Jane"}, profile),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := "John views Jane"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestContextKeyNilInterface(t *testing.T) {
+	ctx := gx.NewContext()
+	key := gx.NewKey[error]("error")
+	key.Push(ctx, nil)
+
+	if err, ok := key.SafeUse(ctx); !ok || err != nil {
+		t.Errorf("expected a nil error to be found, got %v, %v", err, ok)
+	}
+	if err := key.Use(ctx); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestContextKeysDoNotCollideWithTypes(t *testing.T) {
+	title := gx.NewKey[string]("title")
+	ctx := gx.NewContext()
+
+	ctx.Push("pushed")
+	title.Push(ctx, "title")
+
+	if value := gx.Use[string](ctx); value != "pushed" {
+		t.Errorf("expected 'pushed', got %q", value)
+	}
+	if value := title.Use(ctx); value != "title" {
+		t.Errorf("expected 'title', got %q", value)
+	}
+	if _, ok := gx.NewKey[string]("title").SafeUse(ctx); ok {
+		t.Error("expected keys with the same name to be distinct")
+	}
+}
+
+func TestContextFromStdContext(t *testing.T) {
+	type requestIDKey struct{}
+	std := context.WithValue(context.Background(), requestIDKey{}, "req-42")
+	ctx := gx.NewContextFrom(std)
+	var buf strings.Builder
+
+	node := gx.Div(gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Text(c.Context().Value(requestIDKey{}).(string))
+	}))
+
+	if err := node.Render(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<div>req-42</div>"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestContextCancellationStopsRendering(t *testing.T) {
+	std, cancel := context.WithCancel(context.Background())
+	ctx := gx.NewContextFrom(std)
+	var buf strings.Builder
+
+	node := gx.Ul(
+		gx.Li(gx.Text("first")),
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			cancel()
+			return gx.Li(gx.Text("second"))
+		}),
+		gx.Li(gx.Text("third")),
+	)
+
+	err := node.Render(ctx, &buf)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if strings.Contains(buf.String(), "second") || strings.Contains(buf.String(), "third") {
+		t.Errorf("expected rendering to stop after cancellation, got %q", buf.String())
+	}
+}
+
+func TestNewContextHasBackgroundContext(t *testing.T) {
+	if gx.NewContext().Context() != context.Background() {
+		t.Error("expected NewContext to use context.Background")
+	}
+}
+
+type greeter interface {
+	Greet(name string) string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet(name string) string { return "Hello " + name }
+
+type frenchGreeter struct{}
+
+func (frenchGreeter) Greet(name string) string { return "Bonjour " + name }
+
+func TestUseInterface(t *testing.T) {
+	ctx := gx.NewContext()
+
+	if _, ok := gx.SafeUse[greeter](ctx); ok {
+		t.Fatal("expected no greeter to be found")
+	}
+
+	ctx.Push(englishGreeter{})
+	if g := gx.Use[greeter](ctx); g == nil || g.Greet("John") != "Hello John" {
+		t.Fatalf("expected concrete value to be found through its interface, got %v", g)
+	}
+
+	ctx.Push(frenchGreeter{})
+	if g := gx.Use[greeter](ctx); g.Greet("John") != "Bonjour John" {
+		t.Errorf("expected most recent implementation, got %q", g.Greet("John"))
+	}
+
+	gx.PushAs[greeter](ctx, englishGreeter{})
+	if g := gx.Use[greeter](ctx); g.Greet("John") != "Hello John" {
+		t.Errorf("expected value pushed as the interface, got %q", g.Greet("John"))
+	}
+}
+
+func TestProvideAsInterface(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	greeting := gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.P(gx.Text(gx.Use[greeter](c).Greet("John")))
+	})
+
+	node := gx.Div(
+		gx.ProvideAs[greeter](englishGreeter{},
+			greeting,
+			gx.Provide(frenchGreeter{}, greeting),
+		),
+		gx.Provide(frenchGreeter{}, greeting),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<div><p>Hello John</p><p>Hello John</p><p>Bonjour John</p></div>`
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestUseNilInterface(t *testing.T) {
+	ctx := gx.NewContext()
+	gx.PushAs[greeter](ctx, nil)
+
+	if g, ok := gx.SafeUse[greeter](ctx); !ok || g != nil {
+		t.Errorf("expected a nil greeter to be found, got %v, %v", g, ok)
+	}
+
+	var buf strings.Builder
+	node := gx.ProvideAs[greeter](nil, gx.WithContext(func(c *gx.Context) gx.Node {
+		_, ok := gx.SafeUse[greeter](c)
+		return gx.Textf("%v %v", gx.Use[greeter](c), ok)
+	}))
+	if err := node.Render(gx.NewContext(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "&lt;nil&gt; true"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
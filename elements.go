@@ -1,9 +1,9 @@
 package gx
 
 import (
-	"bytes"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 )
 
@@ -30,50 +30,157 @@ type Element struct {
 }
 
 func (e *Element) Render(c *Context, w io.Writer) error {
-	if _, err := w.Write([]byte("<" + e.tag)); err != nil {
-		return err
+	if err := e.renderElement(c, w); err != nil {
+		return wrapRenderError(e, err)
 	}
+	return nil
+}
 
-	attrs := make(map[string]string)
-	var contentChildren []Node
-
-	for i := range e.children {
-		if attr, ok := e.children[i].(*attrNode); ok {
-			attrs[attr.key] = attr.value
-		} else {
-			contentChildren = append(contentChildren, e.children[i])
-		}
+func (e *Element) renderElement(c *Context, w io.Writer) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
 	}
-
-	for attr, value := range attrs {
-		if _, err := fmt.Fprintf(w, ` %s="%s"`, attr, value); err != nil {
+	if children, expanded := expandMaps(e.children); expanded {
+		e = &Element{e.tag, children}
+	}
+	if c.strict {
+		if err := e.check(); err != nil {
 			return err
 		}
 	}
+	if c.observer != nil {
+		c.observer.open(e)
+		err := e.render(c, w)
+		c.observer.close(e)
+		return err
+	}
+	return e.render(c, w)
+}
 
+func (e *Element) render(c *Context, w io.Writer) error {
+	if c.minify != nil {
+		return c.minify.render(c, w, e)
+	}
+	if c.layout != nil && c.layout.preserve == 0 {
+		return c.layout.render(c, w, e)
+	}
+
+	if err := e.renderOpen(c, w); err != nil {
+		return err
+	}
 	if voidElements[e.tag] {
-		_, err := w.Write([]byte(">"))
+		return nil
+	}
+	if err := e.renderContent(c, w); err != nil {
 		return err
 	}
+	return writeStrings(w, "</", e.tag, ">")
+}
 
-	if len(contentChildren) == 0 {
-		_, err := w.Write([]byte("></" + e.tag + ">"))
+func (e *Element) renderOpen(c *Context, w io.Writer) error {
+	if err := writeStrings(w, "<", e.tag); err != nil {
 		return err
 	}
-
-	if _, err := w.Write([]byte(">")); err != nil {
+	if err := e.renderAttrs(c, w); err != nil {
 		return err
 	}
+	_, err := io.WriteString(w, ">")
+	return err
+}
+
+func (e *Element) renderContent(c *Context, w io.Writer) error {
+	parent := c.tag
+	c.tag = e.tag
+	err := renderContentNodes(c, w, e.children)
+	c.tag = parent
+	if err != nil {
+		return e.indexRenderError(err)
+	}
+	return nil
+}
 
-	for i := range contentChildren {
-		if err := contentChildren[i].Render(c, w); err != nil {
+// renderContentNodes renders nodes as the content of an element, leaving out
+// the attributes found by renderAttrs.
+func renderContentNodes(c *Context, w io.Writer, nodes []Node) error {
+	for i := range nodes {
+		if err := renderContentNode(c, w, nodes[i]); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	_, err := w.Write([]byte("</" + e.tag + ">"))
-	return err
+func renderContentNode(c *Context, w io.Writer, node Node) error {
+	switch n := node.(type) {
+	case *attrNode, *attrGroupNode:
+		return nil
+	case *fragmentNode:
+		return renderContentNodes(c, w, n.children)
+	case *ifNode:
+		if child := n.chosen(); child != nil {
+			return renderContentNode(c, w, child)
+		}
+		return nil
+	}
+	return node.Render(c, w)
+}
+
+// renderAttrs writes the attributes of the element. Attributes keep the
+// position of their first declaration, a later declaration of the same
+// attribute replaces its value, except for class and style which are merged.
+func (e *Element) renderAttrs(c *Context, w io.Writer) error {
+	// Elements rarely have more attributes, in which case they are resolved
+	// without allocating.
+	var buf [16]attrSlot
+	slots := buf[:0]
+	for _, attr := range attrs(e.children) {
+		i := slices.IndexFunc(slots, func(s attrSlot) bool { return s.key == attr.key })
+		if i < 0 {
+			slots = append(slots, attrSlot{key: attr.key, last: attr})
+			continue
+		}
+		slots[i].last = attr
+		slots[i].merged = true
+	}
 
+	for _, slot := range slots {
+		var err error
+		switch {
+		case slot.merged && slot.key == "class":
+			err = writeClassAttr(w, e.children)
+		case slot.merged && slot.key == "style":
+			err = writeStyleAttr(w, e.children)
+		default:
+			err = writeAttr(c, w, slot.last)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attrSlot is an attribute of an element, in the order of first declaration.
+type attrSlot struct {
+	key string
+	// last is its last declaration, which gives its value.
+	last *attrNode
+	// merged is set when it is declared more than once.
+	merged bool
+}
+
+// attr returns the value of the attribute key, as rendered.
+func (e *Element) attr(key string) (string, bool) {
+	var last *attrNode
+	for _, attr := range attrs(e.children) {
+		if attr.key == key {
+			last = attr
+		}
+	}
+	if last == nil {
+		return "", false
+	}
+	return last.value, true
 }
 
 func Html(children ...Node) Node {
@@ -367,7 +474,10 @@ type rawNode struct {
 }
 
 func (r *rawNode) Render(c *Context, w io.Writer) error {
-	_, err := w.Write([]byte(r.text))
+	if c.observer != nil {
+		c.observer.text(r.text)
+	}
+	_, err := io.WriteString(w, r.text)
 	return err
 }
 
@@ -380,8 +490,13 @@ type textNode struct {
 }
 
 func (t *textNode) Render(c *Context, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s", t.text)
-	return err
+	if c.observer != nil {
+		c.observer.text(t.text)
+	}
+	if c.minify != nil && whitespaceInsensitiveElements[c.tag] && strings.Trim(t.text, htmlSpace) == "" {
+		return nil
+	}
+	return escapeText(c, w, t.text)
 }
 
 func Text(text string) Node {
@@ -414,8 +529,11 @@ type doctypeNode struct {
 }
 
 func (d *doctypeNode) Render(c *Context, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "<!DOCTYPE %s>", d.doctype)
-	return err
+	if c.layout != nil {
+		c.layout.begin(w)
+		c.layout.started = true
+	}
+	return writeStrings(w, "<!DOCTYPE ", d.doctype, ">")
 }
 
 func Doctype(doctype string) Node {
@@ -425,72 +543,3 @@ func Doctype(doctype string) Node {
 func DoctypeHTML5() Node {
 	return Doctype("html")
 }
-
-const slotPlaceholder = "<!-- slot -->"
-
-type slotNode struct{}
-
-func (s *slotNode) Render(c *Context, w io.Writer) error {
-	_, err := w.Write([]byte(slotPlaceholder))
-	return err
-}
-
-func Slot() Node {
-	return &slotNode{}
-}
-
-type CompiledTemplate struct {
-	beforeSlot string
-	afterSlot  string
-}
-
-func (t *CompiledTemplate) Render(children ...Node) Node {
-	return &compiledNode{
-		template: t,
-		children: children,
-	}
-}
-
-type compiledNode struct {
-	template *CompiledTemplate
-	children []Node
-}
-
-func (cn *compiledNode) Render(c *Context, w io.Writer) error {
-	if _, err := w.Write([]byte(cn.template.beforeSlot)); err != nil {
-		return err
-	}
-
-	for i := range cn.children {
-		if err := cn.children[i].Render(c, w); err != nil {
-			return err
-		}
-	}
-
-	_, err := w.Write([]byte(cn.template.afterSlot))
-	return err
-}
-
-func Compile(template Node) (*CompiledTemplate, error) {
-	ctx := NewContext()
-	var buf bytes.Buffer
-
-	if err := template.Render(ctx, &buf); err != nil {
-		return nil, err
-	}
-
-	html := buf.String()
-
-	parts := strings.Split(html, slotPlaceholder)
-	if len(parts) != 2 {
-		return &CompiledTemplate{
-			beforeSlot: html,
-			afterSlot:  "",
-		}, nil
-	}
-
-	return &CompiledTemplate{
-		beforeSlot: parts[0],
-		afterSlot:  parts[1],
-	}, nil
-}
@@ -143,3 +143,207 @@ func TestClosingElementsWithoutChildren(t *testing.T) {
 		t.Errorf("expected '%q', got '%q'", expected, buf.String())
 	}
 }
+
+func TestAttributesOrder(t *testing.T) {
+	node := gx.Input(
+		gx.Type("text"),
+		gx.Name("email"),
+		gx.ID("email"),
+		gx.Placeholder("Email"),
+		gx.Data("a", "1"),
+		gx.Data("b", "2"),
+		gx.Required(),
+	)
+
+	expected := `<input type="text" name="email" id="email" placeholder="Email" data-a="1" data-b="2" required>`
+	for range 20 {
+		var buf strings.Builder
+		node.Render(gx.NewContext(), &buf)
+		if buf.String() != expected {
+			t.Fatalf("expected '%q', got '%q'", expected, buf.String())
+		}
+	}
+}
+
+func TestDuplicateAttributes(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(gx.ID("first"), gx.Title_("title"), gx.ID("second"))
+
+	node.Render(ctx, &buf)
+
+	expected := `<div id="second" title="title"></div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestClassMerging(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(
+		gx.Class("btn"),
+		gx.ID("submit"),
+		gx.Class("btn-primary btn"),
+		gx.Style_("color: red;"),
+		gx.Style_("margin: 0"),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<div class="btn btn-primary" id="submit" style="color: red; margin: 0"></div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestClasses(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Button(
+		gx.Class("btn"),
+		gx.Classes(map[string]bool{
+			"btn":      true,
+			"disabled": false,
+			"active":   true,
+			"large":    true,
+		}),
+		gx.Classes(map[string]bool{"hidden": false}),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<button class="btn active large"></button>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestBooleanAttributes(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Input(
+		gx.Type("checkbox"),
+		gx.Checked(true),
+		gx.Disabled(false),
+		gx.Required(true, false),
+		gx.Readonly(),
+		gx.BoolAttr("inert"),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<input type="checkbox" checked readonly inert>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestConditionalAttributes(t *testing.T) {
+	for _, active := range []bool{true, false} {
+		ctx := gx.NewContext()
+		var buf strings.Builder
+
+		node := gx.Button(
+			gx.Class("btn"),
+			gx.If(active, gx.Class("active")),
+			gx.IfElse(active, gx.Attr("aria-pressed", "true"), gx.Attr("aria-pressed", "false")),
+			gx.If(!active, gx.Disabled()),
+			gx.If(active, gx.Text("On")),
+		)
+
+		node.Render(ctx, &buf)
+
+		expected := `<button class="btn" aria-pressed="false" disabled></button>`
+		if active {
+			expected = `<button class="btn active" aria-pressed="true">On</button>`
+		}
+		if buf.String() != expected {
+			t.Errorf("expected '%q', got '%q'", expected, buf.String())
+		}
+	}
+}
+
+func TestNestedAttributes(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	button := func(primary bool) gx.Node {
+		return gx.AttrGroup(
+			gx.Type("button"),
+			gx.Class("btn"),
+			gx.If(primary, gx.Class("btn-primary")),
+		)
+	}
+
+	node := gx.Button(
+		gx.ID("save"),
+		button(true),
+		gx.Fragment(gx.Data("action", "save"), gx.Text("Save")),
+		gx.Iff(true, func() gx.Node { return gx.Style_("color: red") }),
+		gx.Map([]string{"a", "b"}, func(item string, _ int) gx.Node {
+			return gx.Data(item, item)
+		}),
+		gx.Attrs(map[string]string{"title": "Save", "aria-label": "Save", "class": "large"}),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<button id="save" type="button" class="btn btn-primary large" data-action="save" style="color: red" data-a="a" data-b="b" aria-label="Save" title="Save">Save</button>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestMapContent(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	calls := 0
+	node := gx.Ul(gx.Map([]string{"a", "b"}, func(item string, _ int) gx.Node {
+		calls++
+		return gx.Li(gx.Class(item), gx.Text(item))
+	}))
+
+	node.Render(ctx, &buf)
+
+	expected := `<ul><li class="a">a</li><li class="b">b</li></ul>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+	if calls != 2 {
+		t.Errorf("expected the function to be called 2 times, got %d", calls)
+	}
+}
+
+func TestAttrGroupOutsideElement(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	gx.AttrGroup(gx.ID("a"), gx.Text("ignored"), gx.Disabled()).Render(ctx, &buf)
+
+	expected := ` id="a" disabled`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestAttributeInComponent(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Class("x")
+	}))
+
+	node.Render(ctx, &buf)
+
+	expected := `<div> class="x"</div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
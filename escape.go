@@ -0,0 +1,134 @@
+package gx
+
+import (
+	"io"
+	"strings"
+)
+
+// rawTextElements hold text that the HTML parser never decodes entities in, so
+// their content is guarded against closing the element early instead of being
+// entity-escaped.
+var rawTextElements = map[string]bool{
+	"script": true,
+	"style":  true,
+}
+
+// urlAttributes are attributes whose value is parsed by the browser as a URL.
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"codebase":   true,
+	"data":       true,
+	"formaction": true,
+	"href":       true,
+	"icon":       true,
+	"longdesc":   true,
+	"manifest":   true,
+	"ping":       true,
+	"poster":     true,
+	"src":        true,
+	"xlink:href": true,
+}
+
+// writeEscaped writes s to w, replacing each byte for which replace returns a
+// non-empty string.
+func writeEscaped(w io.Writer, s string, replace func(s string, i int) string) error {
+	last := 0
+	for i := 0; i < len(s); i++ {
+		esc := replace(s, i)
+		if esc == "" {
+			continue
+		}
+		if _, err := io.WriteString(w, s[last:i]); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, esc); err != nil {
+			return err
+		}
+		last = i + 1
+	}
+	_, err := io.WriteString(w, s[last:])
+	return err
+}
+
+func htmlReplacement(s string, i int) string {
+	switch s[i] {
+	case '&':
+		return "&amp;"
+	case '<':
+		return "&lt;"
+	case '>':
+		return "&gt;"
+	case '"':
+		return "&#34;"
+	case '\'':
+		return "&#39;"
+	case 0:
+		return "\uFFFD"
+	}
+	return ""
+}
+
+// rawTextReplacement keeps text inside <script> and <style> from terminating
+// the element or opening an HTML comment. Only "</script", "</style", in any
+// case, and "<!--" are changed, so that other code such as a<!b is kept as is.
+func rawTextReplacement(s string, i int) string {
+	if s[i] == '<' {
+		rest := s[i+1:]
+		if hasPrefixFold(rest, "/script") || hasPrefixFold(rest, "/style") || strings.HasPrefix(rest, "!--") {
+			return `<\`
+		}
+	}
+	if s[i] == 0 {
+		return "\uFFFD"
+	}
+	return ""
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// urlReplacement percent-encodes every byte that is not valid in a URL and
+// escapes the ampersand for the surrounding attribute value.
+func urlReplacement(s string, i int) string {
+	b := s[i]
+	switch {
+	case b == '&':
+		return "&amp;"
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return ""
+	}
+	switch b {
+	case '-', '.', '_', '~', '!', '#', '$', '*', '+', ',', '/', ':', ';', '=', '?', '@', '[', ']', '%':
+		return ""
+	}
+	return percentEncoded[b]
+}
+
+var percentEncoded = func() (table [256]string) {
+	const hex = "0123456789ABCDEF"
+	for i := range table {
+		table[i] = string([]byte{'%', hex[i>>4], hex[i&0x0f]})
+	}
+	return table
+}()
+
+// escapeText writes element content, taking into account whether it ends up
+// inside a raw text element.
+func escapeText(c *Context, w io.Writer, s string) error {
+	if rawTextElements[c.tag] {
+		return writeEscaped(w, s, rawTextReplacement)
+	}
+	return writeEscaped(w, s, htmlReplacement)
+}
+
+// escapeAttrValue writes an attribute value meant to be wrapped in double
+// quotes.
+func escapeAttrValue(w io.Writer, key, value string) error {
+	if urlAttributes[strings.ToLower(key)] {
+		return writeEscaped(w, value, urlReplacement)
+	}
+	return writeEscaped(w, value, htmlReplacement)
+}
@@ -0,0 +1,106 @@
+package gx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestTextEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.P(gx.Text(`<script>alert("x")</script> & 'y'`))
+
+	node.Render(ctx, &buf)
+
+	expected := `<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &#39;y&#39;</p>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestTextfEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Textf("Hello %s", "<b>John</b>")
+
+	node.Render(ctx, &buf)
+
+	expected := `Hello &lt;b&gt;John&lt;/b&gt;`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestAttributeEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Input(gx.Placeholder(`"><script>alert(1)</script>`))
+
+	node.Render(ctx, &buf)
+
+	expected := `<input placeholder="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestURLAttributeEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.A(gx.Href(`/search?q=a b&lang="en"`), gx.Text("search"))
+
+	node.Render(ctx, &buf)
+
+	expected := `<a href="/search?q=a%20b&amp;lang=%22en%22">search</a>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestScriptTextEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Script(gx.Text(`var s = "</script><script>alert(1)</script>"; if (a < b && c) {}`))
+
+	node.Render(ctx, &buf)
+
+	expected := `<script>var s = "<\/script><script>alert(1)<\/script>"; if (a < b && c) {}</script>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestScriptTextKeepsValidCode(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Script(gx.Text(`if (a<!b && c</d) {} "</SCRIPT >" "<!-- x"`))
+
+	node.Render(ctx, &buf)
+
+	expected := `<script>if (a<!b && c</d) {} "<\/SCRIPT >" "<\!-- x"</script>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestStyleTextEscaping(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(gx.Style(gx.Text(`a > b { content: "</style>" }`)), gx.Text("a > b"))
+
+	node.Render(ctx, &buf)
+
+	expected := `<div><style>a > b { content: "<\/style>" }</style>a &gt; b</div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
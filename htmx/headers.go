@@ -0,0 +1,118 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// IsRequest reports whether r was made by htmx.
+func IsRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted reports whether r was made by an element using hx-boost.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// IsHistoryRestore reports whether r restores the history after a cache
+// miss.
+func IsHistoryRestore(r *http.Request) bool {
+	return r.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// CurrentURL returns the URL of the browser when r was made.
+func CurrentURL(r *http.Request) string {
+	return r.Header.Get("HX-Current-URL")
+}
+
+// TargetID returns the id of the target element of r.
+func TargetID(r *http.Request) string {
+	return r.Header.Get("HX-Target")
+}
+
+// TriggerID returns the id of the element that triggered r.
+func TriggerID(r *http.Request) string {
+	return r.Header.Get("HX-Trigger")
+}
+
+// TriggerName returns the name of the element that triggered r.
+func TriggerName(r *http.Request) string {
+	return r.Header.Get("HX-Trigger-Name")
+}
+
+// PromptResponse returns the answer to hx-prompt.
+func PromptResponse(r *http.Request) string {
+	return r.Header.Get("HX-Prompt")
+}
+
+// Response sets htmx response headers, typically from the header given by
+// gx.ResponseHeader:
+//
+//	htmx.Response(gx.ResponseHeader(c)).Redirect("/login")
+type Response http.Header
+
+func (r Response) set(key, value string) {
+	http.Header(r).Set(key, value)
+}
+
+// Redirect makes the client do a full page redirect to url.
+func (r Response) Redirect(url string) {
+	r.set("HX-Redirect", url)
+}
+
+// Location makes the client navigate to path without a full page reload.
+func (r Response) Location(path string) {
+	r.set("HX-Location", path)
+}
+
+// Refresh makes the client do a full page refresh.
+func (r Response) Refresh() {
+	r.set("HX-Refresh", "true")
+}
+
+func (r Response) PushURL(url string) {
+	r.set("HX-Push-Url", url)
+}
+
+func (r Response) ReplaceURL(url string) {
+	r.set("HX-Replace-Url", url)
+}
+
+func (r Response) Reswap(strategy SwapStrategy) {
+	r.set("HX-Reswap", string(strategy))
+}
+
+func (r Response) Retarget(selector string) {
+	r.set("HX-Retarget", selector)
+}
+
+func (r Response) Reselect(selector string) {
+	r.set("HX-Reselect", selector)
+}
+
+// Trigger triggers events on the client as soon as the response is received.
+func (r Response) Trigger(events ...string) {
+	r.set("HX-Trigger", strings.Join(events, ", "))
+}
+
+// TriggerDetails triggers events with details, encoded as JSON.
+func (r Response) TriggerDetails(events map[string]any) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	r.set("HX-Trigger", string(data))
+	return nil
+}
+
+// TriggerAfterSettle triggers events after the settle step.
+func (r Response) TriggerAfterSettle(events ...string) {
+	r.set("HX-Trigger-After-Settle", strings.Join(events, ", "))
+}
+
+// TriggerAfterSwap triggers events after the swap step.
+func (r Response) TriggerAfterSwap(events ...string) {
+	r.set("HX-Trigger-After-Swap", strings.Join(events, ", "))
+}
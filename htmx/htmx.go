@@ -0,0 +1,190 @@
+// Package htmx provides gx attributes and HTTP header helpers for htmx.
+package htmx
+
+import (
+	"strings"
+
+	"github.com/bpingris/gx"
+)
+
+func Get(url string) gx.Node {
+	return gx.Attr("hx-get", url)
+}
+
+func Post(url string) gx.Node {
+	return gx.Attr("hx-post", url)
+}
+
+func Put(url string) gx.Node {
+	return gx.Attr("hx-put", url)
+}
+
+func Patch(url string) gx.Node {
+	return gx.Attr("hx-patch", url)
+}
+
+func Delete(url string) gx.Node {
+	return gx.Attr("hx-delete", url)
+}
+
+// SwapStrategy is how the content of a response is swapped in.
+type SwapStrategy string
+
+const (
+	InnerHTML   SwapStrategy = "innerHTML"
+	OuterHTML   SwapStrategy = "outerHTML"
+	TextContent SwapStrategy = "textContent"
+	BeforeBegin SwapStrategy = "beforebegin"
+	AfterBegin  SwapStrategy = "afterbegin"
+	BeforeEnd   SwapStrategy = "beforeend"
+	AfterEnd    SwapStrategy = "afterend"
+	SwapDelete  SwapStrategy = "delete"
+	SwapNone    SwapStrategy = "none"
+)
+
+// Swap sets hx-swap, modifiers such as "swap:1s" or "scroll:top" are
+// appended to the strategy.
+func Swap(strategy SwapStrategy, modifiers ...string) gx.Node {
+	value := string(strategy)
+	if len(modifiers) > 0 {
+		value += " " + strings.Join(modifiers, " ")
+	}
+	return gx.Attr("hx-swap", value)
+}
+
+// SwapOOB sets hx-swap-oob, value is "true", a strategy or a strategy
+// followed by a selector.
+func SwapOOB(value string) gx.Node {
+	return gx.Attr("hx-swap-oob", value)
+}
+
+func Target(selector string) gx.Node {
+	return gx.Attr("hx-target", selector)
+}
+
+func Select(selector string) gx.Node {
+	return gx.Attr("hx-select", selector)
+}
+
+func SelectOOB(selectors string) gx.Node {
+	return gx.Attr("hx-select-oob", selectors)
+}
+
+// Trigger sets hx-trigger to the given events.
+func Trigger(events ...Event) gx.Node {
+	specs := make([]string, len(events))
+	for i := range events {
+		specs[i] = events[i].String()
+	}
+	return gx.Attr("hx-trigger", strings.Join(specs, ", "))
+}
+
+func PushURL(url string) gx.Node {
+	return gx.Attr("hx-push-url", url)
+}
+
+func ReplaceURL(url string) gx.Node {
+	return gx.Attr("hx-replace-url", url)
+}
+
+func Boost(enabled bool) gx.Node {
+	return gx.Attr("hx-boost", boolString(enabled))
+}
+
+func Confirm(message string) gx.Node {
+	return gx.Attr("hx-confirm", message)
+}
+
+func Prompt(message string) gx.Node {
+	return gx.Attr("hx-prompt", message)
+}
+
+func Indicator(selector string) gx.Node {
+	return gx.Attr("hx-indicator", selector)
+}
+
+func Include(selector string) gx.Node {
+	return gx.Attr("hx-include", selector)
+}
+
+// Params sets hx-params, e.g. "*", "none" or "not secret".
+func Params(params string) gx.Node {
+	return gx.Attr("hx-params", params)
+}
+
+// Sync sets hx-sync, e.g. "closest form:abort".
+func Sync(sync string) gx.Node {
+	return gx.Attr("hx-sync", sync)
+}
+
+func Ext(extensions ...string) gx.Node {
+	return gx.Attr("hx-ext", strings.Join(extensions, ", "))
+}
+
+func Encoding(encoding string) gx.Node {
+	return gx.Attr("hx-encoding", encoding)
+}
+
+func MultipartEncoding() gx.Node {
+	return Encoding("multipart/form-data")
+}
+
+func Disable() gx.Node {
+	return gx.Attr("hx-disable", "true")
+}
+
+func DisabledElt(selector string) gx.Node {
+	return gx.Attr("hx-disabled-elt", selector)
+}
+
+func Disinherit(attributes ...string) gx.Node {
+	return gx.Attr("hx-disinherit", strings.Join(attributes, " "))
+}
+
+func Inherit(attributes ...string) gx.Node {
+	return gx.Attr("hx-inherit", strings.Join(attributes, " "))
+}
+
+func History(enabled bool) gx.Node {
+	return gx.Attr("hx-history", boolString(enabled))
+}
+
+func HistoryElt() gx.Node {
+	return gx.Attr("hx-history-elt", "true")
+}
+
+func Preserve() gx.Node {
+	return gx.Attr("hx-preserve", "true")
+}
+
+// Request sets hx-request, e.g. `"timeout": 100`.
+func Request(config string) gx.Node {
+	return gx.Attr("hx-request", config)
+}
+
+func Validate() gx.Node {
+	return gx.Attr("hx-validate", "true")
+}
+
+// On sets hx-on for event, the script is run when the event is triggered.
+func On(event, script string) gx.Node {
+	return gx.Attr("hx-on:"+event, script)
+}
+
+// Vals sets hx-vals to vals encoded as JSON. Rendering fails if vals cannot
+// be encoded.
+func Vals(vals map[string]any) gx.Node {
+	return gx.JSONAttr("hx-vals", vals)
+}
+
+// Headers sets hx-headers to headers encoded as JSON.
+func Headers(headers map[string]string) gx.Node {
+	return gx.JSONAttr("hx-headers", headers)
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,142 @@
+package htmx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bpingris/gx"
+	"github.com/bpingris/gx/htmx"
+)
+
+func render(t *testing.T, node gx.Node) string {
+	t.Helper()
+	var buf strings.Builder
+	if err := node.Render(gx.NewContext(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestAttributes(t *testing.T) {
+	node := gx.Button(
+		htmx.Post("/cart/items"),
+		htmx.Target("#cart"),
+		htmx.Swap(htmx.OuterHTML, "swap:1s"),
+		htmx.Confirm("Add to cart?"),
+		htmx.On("htmx:after-request", "this.reset()"),
+		gx.Text("Add"),
+	)
+
+	expected := `<button hx-post="/cart/items" hx-target="#cart" hx-swap="outerHTML swap:1s" hx-confirm="Add to cart?" hx-on:htmx:after-request="this.reset()">Add</button>`
+	if result := render(t, node); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	node := gx.Input(htmx.Trigger(
+		htmx.NewEvent("keyup").Filter("key=='Enter'").Changed().Delay(500*time.Millisecond),
+		htmx.NewEvent("search").From("body").Once(),
+		htmx.Every(2*time.Second),
+	))
+
+	expected := `<input hx-trigger="keyup[key==&#39;Enter&#39;] changed delay:500ms, search from:body once, every 2s">`
+	if result := render(t, node); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestEventIsImmutable(t *testing.T) {
+	base := htmx.NewEvent("click").Once()
+	delayed := base.Delay(time.Second)
+	throttled := base.Throttle(time.Second)
+
+	if delayed.String() != "click once delay:1s" || throttled.String() != "click once throttle:1s" {
+		t.Errorf("expected events built from the same base to be independent, got %q and %q", delayed, throttled)
+	}
+}
+
+func TestVals(t *testing.T) {
+	node := gx.Div(htmx.Vals(map[string]any{"id": 42, "name": `"quoted" <tag>`}))
+
+	expected := `<div hx-vals="{&#34;id&#34;:42,&#34;name&#34;:&#34;\&#34;quoted\&#34; \u003ctag\u003e&#34;}"></div>`
+	if result := render(t, node); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestValsError(t *testing.T) {
+	var buf strings.Builder
+
+	err := gx.Div(htmx.Vals(map[string]any{"fn": func() {}})).Render(gx.NewContext(), &buf)
+	var unsupported *json.UnsupportedTypeError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected %T, got %v", unsupported, err)
+	}
+}
+
+func TestValsErrorInVoidElement(t *testing.T) {
+	node := gx.Input(gx.Name("q"), htmx.Vals(map[string]any{"fn": func() {}}))
+
+	for _, ctx := range []*gx.Context{gx.NewContext(), gx.NewContext(gx.WithStrict())} {
+		err := node.Render(ctx, io.Discard)
+		var unsupported *json.UnsupportedTypeError
+		if !errors.As(err, &unsupported) {
+			t.Errorf("expected %T, got %v", unsupported, err)
+		}
+		if errors.Is(err, gx.ErrVoidContent) {
+			t.Errorf("expected the error not to be reported as content, got %v", err)
+		}
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if htmx.IsRequest(r) {
+		t.Error("expected request without HX-Request not to be an htmx request")
+	}
+
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "cart")
+	if !htmx.IsRequest(r) {
+		t.Error("expected htmx request")
+	}
+	if htmx.TargetID(r) != "cart" {
+		t.Errorf("expected target 'cart', got %q", htmx.TargetID(r))
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+
+	err := gx.RenderHTTP(w, r, http.StatusOK, gx.WithContext(func(c *gx.Context) gx.Node {
+		res := htmx.Response(gx.ResponseHeader(c))
+		res.Redirect("/dashboard")
+		res.Reswap(htmx.SwapNone)
+		if err := res.TriggerDetails(map[string]any{"loggedIn": map[string]string{"user": "john"}}); err != nil {
+			t.Fatal(err)
+		}
+		return gx.Text("ok")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"HX-Redirect": "/dashboard",
+		"HX-Reswap":   "none",
+		"HX-Trigger":  `{"loggedIn":{"user":"john"}}`,
+	}
+	for key, value := range expected {
+		if w.Header().Get(key) != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, w.Header().Get(key))
+		}
+	}
+}
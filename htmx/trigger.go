@@ -0,0 +1,102 @@
+package htmx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event describes an event triggering a request, built with its modifiers:
+//
+//	htmx.NewEvent("keyup").Changed().Delay(500 * time.Millisecond)
+type Event struct {
+	name      string
+	filter    string
+	modifiers []string
+}
+
+func NewEvent(name string) Event {
+	return Event{name: name}
+}
+
+// Every polls at the given interval.
+func Every(interval time.Duration) Event {
+	return Event{name: "every " + formatDuration(interval)}
+}
+
+// Load triggers on load.
+func Load() Event {
+	return Event{name: "load"}
+}
+
+// Revealed triggers when the element is scrolled into the viewport.
+func Revealed() Event {
+	return Event{name: "revealed"}
+}
+
+func (e Event) with(modifier string) Event {
+	e.modifiers = append(e.modifiers[:len(e.modifiers):len(e.modifiers)], modifier)
+	return e
+}
+
+// Filter only triggers when the JavaScript expression is true, e.g.
+// "ctrlKey".
+func (e Event) Filter(expression string) Event {
+	e.filter = expression
+	return e
+}
+
+func (e Event) Once() Event {
+	return e.with("once")
+}
+
+func (e Event) Changed() Event {
+	return e.with("changed")
+}
+
+func (e Event) Delay(d time.Duration) Event {
+	return e.with("delay:" + formatDuration(d))
+}
+
+func (e Event) Throttle(d time.Duration) Event {
+	return e.with("throttle:" + formatDuration(d))
+}
+
+// From listens for the event on the elements matching selector.
+func (e Event) From(selector string) Event {
+	return e.with("from:" + selector)
+}
+
+// Target only triggers when the event target matches selector.
+func (e Event) Target(selector string) Event {
+	return e.with("target:" + selector)
+}
+
+func (e Event) Consume() Event {
+	return e.with("consume")
+}
+
+// Queue sets how events are queued while a request is in flight: "first",
+// "last", "all" or "none".
+func (e Event) Queue(queue string) Event {
+	return e.with("queue:" + queue)
+}
+
+func (e Event) String() string {
+	var b strings.Builder
+	b.WriteString(e.name)
+	if e.filter != "" {
+		b.WriteString("[" + e.filter + "]")
+	}
+	for _, modifier := range e.modifiers {
+		b.WriteString(" " + modifier)
+	}
+	return b.String()
+}
+
+func formatDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
@@ -0,0 +1,102 @@
+package gx
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+var (
+	requestKey        = NewKey[*http.Request]("request")
+	responseHeaderKey = NewKey[http.Header]("response header")
+)
+
+// Request returns the request being answered by RenderHTTP, or nil.
+func Request(c *Context) *http.Request {
+	return requestKey.Use(c)
+}
+
+// ResponseHeader returns the header of the response written by RenderHTTP.
+// Since the response is written once the node is rendered, components can
+// set or delete headers from it. Changes are discarded if rendering fails, or
+// when not rendering with RenderHTTP.
+func ResponseHeader(c *Context) http.Header {
+	if header, ok := responseHeaderKey.SafeUse(c); ok {
+		return header
+	}
+	return http.Header{}
+}
+
+// maxPooledResponse keeps unusually large responses from being held by the
+// pool.
+const maxPooledResponse = 1 << 20
+
+var responsePool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// RenderHTTP renders node as the response to r with the given status code.
+// The node is fully rendered before anything is written, so that a rendering
+// error results in a 500 Internal Server Error response, in which case the
+// error is returned. Flush nodes therefore have no effect, streaming a page
+// requires rendering it to w with Render instead. The Context given to the
+// node is bound to the context of r and gives access to it through Request.
+func RenderHTTP(w http.ResponseWriter, r *http.Request, status int, node Node, opts ...ContextOption) error {
+	c := NewContextFrom(r.Context(), opts...)
+	requestKey.Push(c, r)
+	componentHeader := w.Header().Clone()
+	responseHeaderKey.Push(c, componentHeader)
+
+	buf := responsePool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledResponse {
+			responsePool.Put(buf)
+		}
+	}()
+
+	if err := node.Render(c, buf); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+
+	header := w.Header()
+	for key := range header {
+		if _, kept := componentHeader[key]; !kept {
+			delete(header, key)
+		}
+	}
+	for key, values := range componentHeader {
+		header[key] = values
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+	header.Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// HandlerFunc is an http.Handler that renders the node returned by the
+// function with RenderHTTP, so Flush nodes have no effect. Rendering errors
+// are logged with the log package, use RenderHTTP directly to handle them
+// otherwise.
+type HandlerFunc func(r *http.Request) Node
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := RenderHTTP(w, r, http.StatusOK, f(r)); err != nil {
+		log.Printf("gx: rendering %s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// Handler returns an http.Handler that renders node for every request.
+func Handler(node Node) http.Handler {
+	return HandlerFunc(func(r *http.Request) Node {
+		return node
+	})
+}
@@ -0,0 +1,134 @@
+package gx_test
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestHandler(t *testing.T) {
+	handler := gx.Handler(gx.Div(gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Text(gx.Request(c).URL.Query().Get("name"))
+	})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?name=John", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("expected html content type, got %q", contentType)
+	}
+	if w.Body.String() != "<div>John</div>" {
+		t.Errorf("expected '<div>John</div>', got %q", w.Body.String())
+	}
+}
+
+func TestRenderHTTPStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+
+	err := gx.RenderHTTP(w, r, http.StatusNotFound, gx.WithContext(func(c *gx.Context) gx.Node {
+		gx.ResponseHeader(c).Set("Cache-Control", "no-store")
+		return gx.H1(gx.Text("Not found"))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "no-store" {
+		t.Errorf("expected header set by the component, got %q", cacheControl)
+	}
+	if w.Body.String() != "<h1>Not found</h1>" {
+		t.Errorf("expected '<h1>Not found</h1>', got %q", w.Body.String())
+	}
+}
+
+func TestRenderHTTPError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	errRender := errors.New("render failed")
+
+	err := gx.RenderHTTP(w, r, http.StatusOK, gx.Div(
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			gx.ResponseHeader(c).Set("HX-Redirect", "/elsewhere")
+			return gx.Text("partial")
+		}),
+		failingNode{errRender},
+	))
+	if !errors.Is(err, errRender) {
+		t.Fatalf("expected %v, got %v", errRender, err)
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if strings.Contains(w.Body.String(), "partial") {
+		t.Errorf("expected partial page not to be written, got %q", w.Body.String())
+	}
+	if w.Header().Get("HX-Redirect") != "" {
+		t.Error("expected headers set by components to be discarded")
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	handler := gx.HandlerFunc(func(r *http.Request) gx.Node {
+		return gx.P(gx.Text(r.Method))
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if w.Body.String() != "<p>POST</p>" {
+		t.Errorf("expected '<p>POST</p>', got %q", w.Body.String())
+	}
+}
+
+func TestRenderHTTPDeleteHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := gx.RenderHTTP(w, r, http.StatusOK, gx.WithContext(func(c *gx.Context) gx.Node {
+		gx.ResponseHeader(c).Del("X-Frame-Options")
+		return gx.Div()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := w.Header().Get("X-Frame-Options"); value != "" {
+		t.Errorf("expected header deleted by the component to be removed, got %q", value)
+	}
+	if value := w.Header().Get("X-Content-Type-Options"); value != "nosniff" {
+		t.Errorf("expected other headers to be kept, got %q", value)
+	}
+}
+
+func TestHandlerLogsError(t *testing.T) {
+	var logs strings.Builder
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	handler := gx.Handler(gx.Div(failingNode{errors.New("render failed")}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/page", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if !strings.Contains(logs.String(), "gx: rendering GET /page: div: render failed") {
+		t.Errorf("expected the error to be logged, got %q", logs.String())
+	}
+}
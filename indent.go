@@ -0,0 +1,153 @@
+package gx
+
+import (
+	"io"
+	"strings"
+)
+
+// blockElements are rendered on their own line when indenting. Whitespace
+// around them does not change the rendered page.
+var blockElements = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"body":       true,
+	"caption":    true,
+	"colgroup":   true,
+	"dd":         true,
+	"details":    true,
+	"dialog":     true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"fieldset":   true,
+	"figcaption": true,
+	"figure":     true,
+	"footer":     true,
+	"form":       true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"head":       true,
+	"header":     true,
+	"hgroup":     true,
+	"hr":         true,
+	"html":       true,
+	"li":         true,
+	"main":       true,
+	"nav":        true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"table":      true,
+	"tbody":      true,
+	"td":         true,
+	"tfoot":      true,
+	"th":         true,
+	"thead":      true,
+	"tr":         true,
+	"ul":         true,
+}
+
+// whitespaceSensitiveElements keep their content exactly as rendered.
+var whitespaceSensitiveElements = map[string]bool{
+	"pre":      true,
+	"script":   true,
+	"style":    true,
+	"textarea": true,
+}
+
+// WithIndent renders HTML with block elements on their own line, indented
+// with indent for each level of nesting. Whitespace is only added where it
+// does not change the rendered page: inline elements and text stay on the
+// line of their parent, and the content of <pre>, <textarea>, <script> and
+// <style> is left as is. It replaces WithMinify. Compiled templates keep the
+// formatting they were compiled with.
+func WithIndent(indent string) ContextOption {
+	return func(c *Context) {
+		c.minify = nil
+		c.layout = &indentLayout{indent: indent}
+	}
+}
+
+type indentLayout struct {
+	indent string
+	depth  int
+	// w is the writer of the current render, a render to another writer
+	// starts over.
+	w io.Writer
+	// started is set once the first element is written, which does not get
+	// a line break before it.
+	started bool
+	// sawBlock is set when a block element was rendered since the current
+	// element was opened, in which case its closing tag goes on its own line.
+	sawBlock bool
+	// preserve counts the whitespace-sensitive elements being rendered.
+	preserve int
+}
+
+// begin starts over when a node is rendered at the top level to another
+// writer than the previous one.
+func (l *indentLayout) begin(w io.Writer) {
+	if l.depth == 0 && l.w != w {
+		l.w, l.started, l.sawBlock = w, false, false
+	}
+}
+
+func (l *indentLayout) newline(w io.Writer, depth int) error {
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, strings.Repeat(l.indent, depth))
+	return err
+}
+
+func (l *indentLayout) render(c *Context, w io.Writer, e *Element) error {
+	l.begin(w)
+	// Everything inside <head> is metadata, which is never rendered.
+	block := blockElements[e.tag] || c.tag == "head"
+	if block && l.started {
+		if err := l.newline(w, l.depth); err != nil {
+			return err
+		}
+	}
+	l.started = true
+
+	if err := e.renderOpen(c, w); err != nil {
+		return err
+	}
+	if voidElements[e.tag] {
+		l.sawBlock = l.sawBlock || block
+		return nil
+	}
+
+	parentSawBlock := l.sawBlock
+	l.sawBlock = false
+	l.depth++
+	preserve := whitespaceSensitiveElements[e.tag]
+	if preserve {
+		l.preserve++
+	}
+	err := e.renderContent(c, w)
+	if preserve {
+		l.preserve--
+	}
+	l.depth--
+	childBlock := l.sawBlock
+	l.sawBlock = parentSawBlock || block || childBlock
+	if err != nil {
+		return err
+	}
+
+	if childBlock {
+		if err := l.newline(w, l.depth); err != nil {
+			return err
+		}
+	}
+	return writeStrings(w, "</", e.tag, ">")
+}
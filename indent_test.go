@@ -0,0 +1,106 @@
+package gx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestIndent(t *testing.T) {
+	ctx := gx.NewContext(gx.WithIndent("  "))
+
+	node := gx.Fragment(
+		gx.DoctypeHTML5(),
+		gx.Html(
+			gx.Head(
+				gx.UTF8Charset(),
+				gx.Title(gx.Text("Title")),
+				gx.InlineCSS("body {\n  margin: 0;\n}"),
+			),
+			gx.Body(
+				gx.Div(
+					gx.Class("card"),
+					gx.H1(gx.Text("Hello "), gx.Em(gx.Text("World"))),
+					gx.P(gx.Text("Some "), gx.A(gx.Href("/"), gx.Text("link")), gx.Text(".")),
+					gx.Ul(gx.Li(gx.Text("one")), gx.Li(gx.Text("two"))),
+				),
+				gx.Pre(gx.Code(gx.Text("line 1\n  line 2")), gx.Div(gx.Text("kept"))),
+				gx.Span(gx.Text("a")),
+				gx.Span(gx.Text("b")),
+				gx.Textarea(gx.P(gx.Text("as is"))),
+			),
+		),
+	)
+
+	expected := `<!DOCTYPE html>
+<html>
+  <head>
+    <meta name="charset" charset="utf-8">
+    <title>Title</title>
+    <style type="text/css">body {
+  margin: 0;
+}</style>
+  </head>
+  <body>
+    <div class="card">
+      <h1>Hello <em>World</em></h1>
+      <p>Some <a href="/">link</a>.</p>
+      <ul>
+        <li>one</li>
+        <li>two</li>
+      </ul>
+    </div>
+    <pre><code>line 1
+  line 2</code><div>kept</div></pre><span>a</span><span>b</span><textarea><p>as is</p></textarea>
+  </body>
+</html>`
+	if result := renderString(t, ctx, node); result != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestIndentOptimized(t *testing.T) {
+	node := gx.Div(gx.P(gx.Text("static")), gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.P(gx.Text("dynamic"))
+	}))
+
+	expected := renderString(t, gx.NewContext(gx.WithIndent("\t")), node)
+	if result := renderString(t, gx.NewContext(gx.WithIndent("\t")), gx.Optimize(node)); result != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestIndentRenderTwice(t *testing.T) {
+	ctx := gx.NewContext(gx.WithIndent("  "))
+	node := gx.Div(gx.P(gx.Text("x")))
+
+	expected := "<div>\n  <p>x</p>\n</div>"
+	for range 2 {
+		if result := renderString(t, ctx, node); result != expected {
+			t.Errorf("expected %q, got %q", expected, result)
+		}
+	}
+	for range 2 {
+		var buf strings.Builder
+		if err := gx.Render(ctx, &buf, node); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("expected %q, got %q", expected, buf.String())
+		}
+	}
+}
+
+func TestIndentPartial(t *testing.T) {
+	ctx := gx.NewContext(gx.WithIndent("  "))
+	page := gx.Html(gx.Body(gx.Main(gx.Region("list", gx.Ul(gx.Li(gx.Text("a")))))))
+
+	expected := "<ul>\n  <li>a</li>\n</ul>"
+	if result := renderString(t, ctx, gx.Partial("list", page)); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	if result := renderString(t, ctx, page); !strings.HasPrefix(result, "<html>\n  <body>") {
+		t.Errorf("expected the page to be indented from the top, got %q", result)
+	}
+}
@@ -0,0 +1,210 @@
+package gx
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithMinify renders HTML as small as possible without changing the page:
+// optional closing tags are omitted, attribute values are only quoted when
+// needed, boolean attributes are written without a value, and whitespace-only
+// text is dropped where it is ignored by browsers. It replaces WithIndent.
+// Compiled templates keep the formatting they were compiled with.
+func WithMinify() ContextOption {
+	return func(c *Context) {
+		c.layout = nil
+		c.minify = &minifier{}
+	}
+}
+
+// booleanAttributes are true when present, whatever their value.
+var booleanAttributes = map[string]bool{
+	"allowfullscreen": true,
+	"async":           true,
+	"autofocus":       true,
+	"autoplay":        true,
+	"checked":         true,
+	"controls":        true,
+	"default":         true,
+	"defer":           true,
+	"disabled":        true,
+	"formnovalidate":  true,
+	"hidden":          true,
+	"inert":           true,
+	"ismap":           true,
+	"itemscope":       true,
+	"loop":            true,
+	"multiple":        true,
+	"muted":           true,
+	"nomodule":        true,
+	"novalidate":      true,
+	"open":            true,
+	"playsinline":     true,
+	"readonly":        true,
+	"required":        true,
+	"reversed":        true,
+	"selected":        true,
+}
+
+// optionalEndTag describes when the end tag of an element can be omitted,
+// following the HTML specification.
+type optionalEndTag struct {
+	// before lists the elements whose start tag can directly follow.
+	before map[string]bool
+	// atParentEnd allows omitting the end tag when the parent ends.
+	atParentEnd bool
+}
+
+var paragraphClosers = tags("address", "article", "aside", "blockquote", "details", "dialog", "div", "dl",
+	"fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header",
+	"hgroup", "hr", "main", "menu", "nav", "ol", "p", "pre", "search", "section", "table", "ul")
+
+var optionalEndTags = map[string]optionalEndTag{
+	"li":       {tags("li"), true},
+	"dt":       {tags("dt", "dd"), false},
+	"dd":       {tags("dt", "dd"), true},
+	"p":        {paragraphClosers, true},
+	"option":   {tags("option", "optgroup"), true},
+	"optgroup": {tags("optgroup"), true},
+	"thead":    {tags("tbody", "tfoot"), false},
+	"tbody":    {tags("tbody", "tfoot"), true},
+	"tfoot":    {nil, true},
+	"tr":       {tags("tr"), true},
+	"td":       {tags("td", "th"), true},
+	"th":       {tags("td", "th"), true},
+}
+
+// paragraphKeepers are the elements in which a <p> must be closed
+// explicitly.
+var paragraphKeepers = tags("a", "audio", "del", "ins", "map", "noscript", "video")
+
+// whitespaceInsensitiveElements ignore text made only of whitespace.
+var whitespaceInsensitiveElements = tags("colgroup", "dl", "head", "html", "ol", "optgroup", "select",
+	"table", "tbody", "tfoot", "thead", "tr", "ul")
+
+func tags(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+type minifier struct {
+	// writers are reused across elements, one per level of nesting.
+	writers []*minifyWriter
+	depth   int
+}
+
+// minifyWriter is given to the children of an element. It holds back the end
+// tag of the last child until it is known whether it can be omitted.
+type minifyWriter struct {
+	w       io.Writer
+	pending string
+}
+
+func (mw *minifyWriter) flushPending() error {
+	if mw.pending == "" {
+		return nil
+	}
+	tag := mw.pending
+	mw.pending = ""
+	return writeStrings(mw.w, "</", tag, ">")
+}
+
+func (mw *minifyWriter) Write(p []byte) (int, error) {
+	if err := mw.flushPending(); err != nil {
+		return 0, err
+	}
+	return mw.w.Write(p)
+}
+
+func (mw *minifyWriter) WriteString(s string) (int, error) {
+	if err := mw.flushPending(); err != nil {
+		return 0, err
+	}
+	return io.WriteString(mw.w, s)
+}
+
+// Flush forwards to the underlying writer, keeping the pending end tag since
+// the next sibling is not known yet.
+func (mw *minifyWriter) Flush() error {
+	switch fw := mw.w.(type) {
+	case interface{ Flush() error }:
+		return fw.Flush()
+	case http.Flusher:
+		fw.Flush()
+	}
+	return nil
+}
+
+// startTag drops the pending end tag if the start tag of tag implies it.
+func (mw *minifyWriter) startTag(tag string) error {
+	if mw.pending != "" && optionalEndTags[mw.pending].before[tag] {
+		mw.pending = ""
+	}
+	return mw.flushPending()
+}
+
+// endParent drops the pending end tag if the end of parent implies it.
+func (mw *minifyWriter) endParent(parent string) error {
+	if mw.pending != "" && optionalEndTags[mw.pending].atParentEnd &&
+		!(mw.pending == "p" && paragraphKeepers[parent]) {
+		mw.pending = ""
+	}
+	return mw.flushPending()
+}
+
+func (m *minifier) render(c *Context, w io.Writer, e *Element) error {
+	parent, hasParent := w.(*minifyWriter)
+	if hasParent {
+		if err := parent.startTag(e.tag); err != nil {
+			return err
+		}
+	}
+	if err := e.renderOpen(c, w); err != nil {
+		return err
+	}
+	if voidElements[e.tag] {
+		return nil
+	}
+
+	if m.depth == len(m.writers) {
+		m.writers = append(m.writers, &minifyWriter{})
+	}
+	mw := m.writers[m.depth]
+	mw.w, mw.pending = w, ""
+	m.depth++
+	err := e.renderContent(c, mw)
+	m.depth--
+	if err != nil {
+		return err
+	}
+	if err := mw.endParent(e.tag); err != nil {
+		return err
+	}
+
+	if _, optional := optionalEndTags[e.tag]; optional && hasParent {
+		parent.pending = e.tag
+		return nil
+	}
+	return writeStrings(w, "</", e.tag, ">")
+}
+
+// writeMinifiedAttr writes an attribute without its value if it is a boolean
+// attribute whose value is empty or its name, or without quotes if they are
+// not needed. Other values of boolean attributes, such as hidden="until-found",
+// have a meaning of their own.
+func writeMinifiedAttr(w io.Writer, key, value string) (bool, error) {
+	if booleanAttributes[key] && (value == "" || strings.EqualFold(value, key)) {
+		return true, writeStrings(w, " ", key)
+	}
+	if value == "" || strings.ContainsAny(value, " \t\n\f\r\"'=<>`") {
+		return false, nil
+	}
+	if err := writeStrings(w, " ", key, "="); err != nil {
+		return true, err
+	}
+	return true, escapeAttrValue(w, key, value)
+}
@@ -0,0 +1,92 @@
+package gx_test
+
+import (
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestMinify(t *testing.T) {
+	ctx := gx.NewContext(gx.WithMinify())
+
+	node := gx.Fragment(
+		gx.DoctypeHTML5(),
+		gx.Html(
+			gx.Lang("en"),
+			gx.Head(gx.Text("\n  "), gx.Title(gx.Text("Title"))),
+			gx.Body(
+				gx.Ul(
+					gx.Text("\n"),
+					gx.Li(gx.Text("one")),
+					gx.Text("\n"),
+					gx.Li(gx.Text("two")),
+					gx.Text("\n"),
+				),
+				gx.P(gx.Text("first")),
+				gx.P(gx.Text("second")),
+				gx.Span(gx.Text(" ")),
+				gx.Input(gx.Type("checkbox"), gx.Name("a b"), gx.Checked(), gx.Disabled(), gx.Placeholder(""),
+					gx.Attr("required", "REQUIRED"), gx.Attr("hidden", "until-found")),
+				gx.A(gx.Href("/a?b=1&c=2"), gx.P(gx.Text("in link"))),
+				gx.Select(gx.Option(gx.Text("a")), gx.Option(gx.Selected(), gx.Text("b"))),
+				gx.P(gx.Text("last")),
+			),
+		),
+	)
+
+	expected := `<!DOCTYPE html><html lang=en><head><title>Title</title></head><body>` +
+		`<ul><li>one<li>two</ul>` +
+		`<p>first<p>second</p><span> </span>` +
+		`<input type=checkbox name="a b" checked disabled placeholder="" required hidden=until-found>` +
+		`<a href="/a?b=1&amp;c=2"><p>in link</p></a>` +
+		`<select><option>a<option selected>b</select>` +
+		`<p>last</body></html>`
+	if result := renderString(t, ctx, node); result != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestMinifyTable(t *testing.T) {
+	ctx := gx.NewContext(gx.WithMinify())
+
+	node := gx.Table(
+		gx.Thead(gx.Tr(gx.Th(gx.Text("a")), gx.Th(gx.Text("b")))),
+		gx.Tbody(
+			gx.Tr(gx.Td(gx.Text("1")), gx.Td(gx.Text("2"))),
+			gx.Tr(gx.Td(gx.Text("3")), gx.Td(gx.Text("4"))),
+		),
+	)
+
+	expected := `<table><thead><tr><th>a<th>b<tbody><tr><td>1<td>2<tr><td>3<td>4</table>`
+	if result := renderString(t, ctx, node); result != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestMinifyKeepsEndTagsBeforeContent(t *testing.T) {
+	ctx := gx.NewContext(gx.WithMinify())
+
+	node := gx.Div(
+		gx.P(gx.Text("paragraph")),
+		gx.Text("text"),
+		gx.P(gx.Text("paragraph")),
+		gx.Span(gx.Text("inline")),
+		gx.Ul(gx.Li(gx.Text("item")), gx.Raw("<li>raw</li>")),
+	)
+
+	expected := `<div><p>paragraph</p>text<p>paragraph</p><span>inline</span><ul><li>item</li><li>raw</li></ul></div>`
+	if result := renderString(t, ctx, node); result != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestMinifyKeepsNonBreakingSpaces(t *testing.T) {
+	ctx := gx.NewContext(gx.WithMinify())
+
+	node := gx.Ul(gx.Text("\u00a0"), gx.Text(" \t\n"), gx.Li(gx.Text("a")))
+
+	expected := "<ul>\u00a0<li>a</ul>"
+	if result := renderString(t, ctx, node); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
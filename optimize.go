@@ -0,0 +1,174 @@
+package gx
+
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+// staticNode is a pre-rendered subtree.
+type staticNode struct {
+	html string
+	// node is rendered instead of html when the context renders HTML
+	// differently than when it was pre-rendered.
+	node Node
+	// urls is set when html contains URLs checked against the
+	// DefaultURLPolicy.
+	urls bool
+}
+
+func (s *staticNode) Render(c *Context, w io.Writer) error {
+	if c.layout != nil || c.minify != nil || c.observer != nil || c.strict ||
+		(s.urls && !c.urlPolicy.equal(DefaultURLPolicy)) {
+		return s.node.Render(c, w)
+	}
+	_, err := io.WriteString(w, s.html)
+	return err
+}
+
+// Optimize returns a tree that renders like node, in which every subtree made
+// only of elements, attributes, text, raw HTML and doctypes is pre-rendered.
+// Other nodes, such as WithContext components, are kept and rendered as usual.
+//
+// Optimizing a tree costs about as much as rendering it, it pays off for
+// trees that are built once and rendered many times. Static parts are
+// rendered with the DefaultURLPolicy, those containing URL attributes are
+// rendered again when the context has another URLPolicy.
+func Optimize(node Node) Node {
+	optimized, static := optimize(node, "")
+	if static {
+		return prerender(optimized, "")
+	}
+	return optimized
+}
+
+// optimize returns the optimized node and whether it is static, in which case
+// it is returned as is for the caller to pre-render it along with its static
+// siblings. tag is the element node is rendered in.
+func optimize(node Node, tag string) (Node, bool) {
+	switch n := node.(type) {
+	case *attrNode, *attrGroupNode, *textNode, *rawNode, *doctypeNode, *staticNode:
+		return n, true
+	case *Element:
+		children, static := optimizeChildren(n.children, n.tag)
+		if static {
+			return n, true
+		}
+		return &Element{n.tag, children}, false
+	case *fragmentNode:
+		children, static := optimizeChildren(n.children, tag)
+		if static {
+			return n, true
+		}
+		return &fragmentNode{children}, false
+	case *provideNode:
+		children, static := optimizeChildren(n.children, tag)
+		if static {
+			children = []Node{prerender(&fragmentNode{n.children}, tag)}
+		}
+		return &provideNode{n.key, n.value, children}, false
+	case *regionNode:
+		children, static := optimizeChildren(n.children, tag)
+		if static {
+			children = []Node{prerender(&fragmentNode{n.children}, tag)}
+		}
+		return &regionNode{n.name, children}, false
+	case *ifNode:
+		return &ifNode{n.condition, optimizeNode(n.trueChild, tag), optimizeNode(n.falseChild, tag)}, false
+	case *repeatNode:
+		return &repeatNode{n.times, optimizeNode(n.child, tag)}, false
+	}
+	return node, false
+}
+
+// optimizeNode optimizes a node that is not rendered along with siblings.
+// Attributes are kept as is so that conditionals around them are still
+// attributes.
+func optimizeNode(node Node, tag string) Node {
+	if node == nil || hasAttrs(node) {
+		return node
+	}
+	optimized, static := optimize(node, tag)
+	if static {
+		return prerender(optimized, tag)
+	}
+	return optimized
+}
+
+// optimizeChildren optimizes each child. When one of them is not static, it
+// returns the optimized children with consecutive static content merged into
+// a single pre-rendered node. Attributes are kept as is so that elements still
+// find them.
+func optimizeChildren(children []Node, tag string) ([]Node, bool) {
+	results := make([]Node, len(children))
+	isStatic := make([]bool, len(children))
+	static := true
+	for i := range children {
+		results[i], isStatic[i] = optimize(children[i], tag)
+		static = static && isStatic[i]
+	}
+	if static {
+		return nil, true
+	}
+
+	optimized := make([]Node, 0, len(children))
+	start := -1
+	flush := func(end int) {
+		switch {
+		case start < 0:
+		case end-start == 1:
+			optimized = append(optimized, prerender(results[start], tag))
+		default:
+			optimized = append(optimized, prerender(&fragmentNode{results[start:end]}, tag))
+		}
+		start = -1
+	}
+
+	for i, child := range results {
+		if !isStatic[i] || hasAttrs(child) {
+			flush(i)
+			optimized = append(optimized, child)
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(results))
+
+	return optimized, false
+}
+
+// prerender renders a static node as if it was inside tag.
+func prerender(node Node, tag string) Node {
+	if _, ok := node.(*staticNode); ok {
+		return node
+	}
+	ctx := NewContext()
+	ctx.tag = tag
+	var b strings.Builder
+	if err := node.Render(ctx, &b); err != nil {
+		return node
+	}
+	return &staticNode{b.String(), node, hasURLAttrs(node)}
+}
+
+// hasURLAttrs reports whether a static node contains URL attributes checked
+// against the URLPolicy.
+func hasURLAttrs(node Node) bool {
+	switch n := node.(type) {
+	case *attrNode:
+		return checkedURLAttr(n.key) && !n.trusted
+	case *attrGroupNode:
+		return slices.ContainsFunc(n.attrs, hasURLAttrs)
+	case *Element:
+		return slices.ContainsFunc(n.children, hasURLAttrs)
+	case *fragmentNode:
+		return slices.ContainsFunc(n.children, hasURLAttrs)
+	case *ifNode:
+		return n.chosen() != nil && hasURLAttrs(n.chosen())
+	case *staticNode:
+		return n.urls
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package gx_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func page() gx.Node {
+	return gx.Fragment(
+		gx.DoctypeHTML5(),
+		gx.Html(
+			gx.Lang("en"),
+			gx.Head(
+				gx.Title(gx.Text("Page")),
+				gx.InlineJS(`if (a < b) {}`),
+				gx.Script(gx.Text(`"</script>"`)),
+			),
+			gx.Body(
+				gx.Class("page"),
+				gx.Header(gx.WithContext(func(c *gx.Context) gx.Node {
+					return gx.Text(gx.Use[string](c))
+				})),
+				gx.Main(
+					gx.ID("main"),
+					gx.If(true, gx.Class("conditional")),
+					gx.Fragment(gx.Data("static", "true"), gx.Text("text")),
+					gx.Attrs(map[string]string{"title": "Main"}),
+					gx.H1(gx.Text("Title & subtitle")),
+					gx.P(gx.Text("static")),
+					gx.If(true, gx.P(gx.Text("conditional"))),
+					gx.Provide(42, gx.WithContext(func(c *gx.Context) gx.Node {
+						return gx.Textf("%d", gx.Use[int](c))
+					})),
+					gx.Ul(gx.Repeat(3, gx.Li(gx.Text("item")))),
+				),
+				gx.Footer(gx.P(gx.Text("footer"))),
+			),
+		),
+	)
+}
+
+func renderString(t testing.TB, ctx *gx.Context, node gx.Node) string {
+	t.Helper()
+	var buf strings.Builder
+	if err := node.Render(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestOptimizeRendersTheSame(t *testing.T) {
+	optimized := gx.Optimize(page())
+
+	for _, user := range []string{"John", "Jane <admin>"} {
+		ctx := gx.NewContext()
+		ctx.Push(user)
+
+		expected := renderString(t, ctx, page())
+		if result := renderString(t, ctx, optimized); result != expected {
+			t.Errorf("expected '%q', got '%q'", expected, result)
+		}
+	}
+}
+
+func TestOptimizeStaticTree(t *testing.T) {
+	ctx := gx.NewContext()
+	tree := gx.Div(gx.Class("a"), gx.Script(gx.Text("</script>")), gx.Img(gx.Src("javascript:x")))
+
+	expected := renderString(t, ctx, tree)
+	if result := renderString(t, ctx, gx.Optimize(tree)); result != expected {
+		t.Errorf("expected '%q', got '%q'", expected, result)
+	}
+}
+
+func TestOptimizeURLPolicy(t *testing.T) {
+	ctx := gx.NewContext(gx.WithURLPolicy(gx.URLPolicy{Schemes: []string{"https"}}))
+	tree := gx.Div(
+		gx.A(gx.Href("http://example.com"), gx.Text("link")),
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			return gx.Text("dynamic")
+		}),
+	)
+
+	expected := `<div><a href="about:invalid#gx-unsafe-url">link</a>dynamic</div>`
+	if result := renderString(t, ctx, tree); result != expected {
+		t.Fatalf("expected '%q', got '%q'", expected, result)
+	}
+	optimized := gx.Optimize(tree)
+	if result := renderString(t, ctx, optimized); result != expected {
+		t.Errorf("expected '%q', got '%q'", expected, result)
+	}
+
+	expected = `<div><a href="http://example.com">link</a>dynamic</div>`
+	if result := renderString(t, gx.NewContext(), optimized); result != expected {
+		t.Errorf("expected '%q', got '%q'", expected, result)
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	tree := page()
+	ctx := gx.NewContext()
+	ctx.Push("John")
+	b.ReportAllocs()
+	for b.Loop() {
+		tree.Render(ctx, io.Discard)
+	}
+}
+
+func BenchmarkRenderOptimized(b *testing.B) {
+	tree := gx.Optimize(page())
+	ctx := gx.NewContext()
+	ctx.Push("John")
+	b.ReportAllocs()
+	for b.Loop() {
+		tree.Render(ctx, io.Discard)
+	}
+}
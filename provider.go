@@ -1,22 +1,40 @@
 package gx
 
-import "io"
+import (
+	"io"
+	"reflect"
+)
 
 type provideNode struct {
+	key      any
 	value    any
 	children []Node
 }
 
+// Render makes the value visible to the children only, restoring whatever
+// was provided before once they are rendered.
 func (p *provideNode) Render(c *Context, w io.Writer) error {
-	c.Push(p.value)
+	if c.compiler != nil {
+		return c.compiler.provide(c, p)
+	}
+
+	prev, exists := c.swap(p.key, p.value)
+	var err error
 	for i := range p.children {
-		if err := p.children[i].Render(c, w); err != nil {
-			return err
+		if err = p.children[i].Render(c, w); err != nil {
+			break
 		}
 	}
-	return nil
+	c.restore(p.key, prev, exists)
+	return err
 }
 
 func Provide(value any, children ...Node) Node {
-	return &provideNode{value, children}
+	return &provideNode{reflect.TypeOf(value), value, children}
+}
+
+// ProvideAs is like Provide but provides value as T, which is typically an
+// interface implemented by value.
+func ProvideAs[T any](value T, children ...Node) Node {
+	return &provideNode{reflect.TypeFor[T](), value, children}
 }
@@ -0,0 +1,102 @@
+package gx_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func currentString() gx.Node {
+	return gx.WithContext(func(c *gx.Context) gx.Node {
+		value, ok := gx.SafeUse[string](c)
+		if !ok {
+			return gx.Text("none")
+		}
+		return gx.Text(value)
+	})
+}
+
+func TestProvideSiblings(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(
+		gx.Provide("first", gx.P(currentString())),
+		gx.P(currentString()),
+		gx.Provide("second", gx.P(currentString())),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<div><p>first</p><p>none</p><p>second</p></div>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestProvideNested(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Provide("outer",
+		gx.P(currentString()),
+		gx.Provide("inner", gx.P(currentString())),
+		gx.P(currentString()),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<p>outer</p><p>inner</p><p>outer</p>`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestProvideDoesNotOverwritePushedValue(t *testing.T) {
+	ctx := gx.NewContext()
+	ctx.Push("pushed")
+	var buf strings.Builder
+
+	node := gx.Fragment(
+		gx.Provide("provided", currentString()),
+		gx.Text(" "),
+		currentString(),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `provided pushed`
+	if buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestProvideRestoresOnError(t *testing.T) {
+	ctx := gx.NewContext()
+	ctx.Push("outer")
+	errRender := errors.New("render failed")
+
+	node := gx.Provide("inner",
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			return failingNode{errRender}
+		}),
+	)
+
+	if err := node.Render(ctx, io.Discard); !errors.Is(err, errRender) {
+		t.Fatalf("expected %v, got %v", errRender, err)
+	}
+	if value := gx.Use[string](ctx); value != "outer" {
+		t.Errorf("expected 'outer' to be restored, got %q", value)
+	}
+}
+
+type failingNode struct {
+	err error
+}
+
+func (f failingNode) Render(c *gx.Context, w io.Writer) error {
+	return f.err
+}
@@ -0,0 +1,93 @@
+package gx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrRegionNotFound = errors.New("gx: region not found")
+
+// errRegionDone stops rendering once the wanted region has been rendered.
+var errRegionDone = errors.New("gx: region rendered")
+
+// regionTarget is the region rendered by a Partial.
+type regionTarget struct {
+	name  string
+	w     io.Writer
+	found bool
+}
+
+type regionNode struct {
+	name     string
+	children []Node
+}
+
+func (r *regionNode) Render(c *Context, w io.Writer) error {
+	if c.compiler != nil {
+		return c.compiler.region(c, r)
+	}
+
+	target := c.region
+	if target == nil || target.name != r.name || target.found {
+		return renderChildren(c, w, r.children)
+	}
+
+	target.found = true
+	layout := c.layout
+	if layout != nil {
+		// The region is rendered as a page of its own.
+		c.layout = &indentLayout{indent: layout.indent, preserve: layout.preserve}
+	}
+	err := renderChildren(c, target.w, r.children)
+	c.layout = layout
+	if err != nil {
+		return err
+	}
+	return errRegionDone
+}
+
+// Region names a part of a page so that it can be rendered on its own with
+// Partial. It renders its children as is.
+func Region(name string, children ...Node) Node {
+	return &regionNode{name, children}
+}
+
+type partialNode struct {
+	name string
+	node Node
+}
+
+func (p *partialNode) Render(c *Context, w io.Writer) error {
+	parent := c.region
+	c.region = &regionTarget{name: p.name, w: w}
+	err := p.node.Render(c, io.Discard)
+	found := c.region.found
+	c.region = parent
+
+	switch {
+	case errors.Is(err, errRegionDone):
+		return nil
+	case err != nil:
+		return err
+	case !found:
+		return fmt.Errorf("%w %q", ErrRegionNotFound, p.name)
+	}
+	return nil
+}
+
+// Partial renders only the children of the region called name in node, e.g.
+// to answer a request for a fragment of a page with the component rendering
+// the whole page. Rendering stops once the region is rendered.
+func Partial(name string, node Node) Node {
+	return &partialNode{name, node}
+}
+
+func renderChildren(c *Context, w io.Writer, children []Node) error {
+	for i := range children {
+		if err := children[i].Render(c, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
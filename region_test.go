@@ -0,0 +1,127 @@
+package gx_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func shopPage(items ...string) gx.Node {
+	return gx.Html(
+		gx.Body(
+			gx.H1(gx.Text("Shop")),
+			gx.Provide("EUR",
+				gx.Div(
+					gx.ID("cart"),
+					gx.Region("cart",
+						gx.Ul(gx.Map(items, func(item string, _ int) gx.Node {
+							return gx.Li(gx.WithContext(func(c *gx.Context) gx.Node {
+								return gx.Textf("%s (%s)", item, gx.Use[string](c))
+							}))
+						})),
+					),
+				),
+			),
+			gx.Footer(gx.Text("footer")),
+		),
+	)
+}
+
+func TestRegionRendersInPage(t *testing.T) {
+	result := renderString(t, gx.NewContext(), shopPage("apple"))
+
+	expected := `<html><body><h1>Shop</h1><div id="cart"><ul><li>apple (EUR)</li></ul></div><footer>footer</footer></body></html>`
+	if result != expected {
+		t.Errorf("expected '%q', got '%q'", expected, result)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	result := renderString(t, gx.NewContext(), gx.Partial("cart", shopPage("apple", "pear")))
+
+	expected := `<ul><li>apple (EUR)</li><li>pear (EUR)</li></ul>`
+	if result != expected {
+		t.Errorf("expected '%q', got '%q'", expected, result)
+	}
+}
+
+func TestPartialNotFound(t *testing.T) {
+	var buf strings.Builder
+
+	err := gx.Partial("missing", shopPage()).Render(gx.NewContext(), &buf)
+	if !errors.Is(err, gx.ErrRegionNotFound) {
+		t.Errorf("expected %v, got %v", gx.ErrRegionNotFound, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", buf.String())
+	}
+}
+
+func TestPartialStopsAfterRegion(t *testing.T) {
+	rendered := false
+	page := gx.Div(
+		gx.Region("first", gx.Text("first")),
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			rendered = true
+			return gx.Text("second")
+		}),
+	)
+
+	result := renderString(t, gx.NewContext(), gx.Partial("first", page))
+
+	if result != "first" {
+		t.Errorf("expected 'first', got %q", result)
+	}
+	if rendered {
+		t.Error("expected rendering to stop after the region")
+	}
+}
+
+func TestPartialOfCompiledTemplate(t *testing.T) {
+	compiled, err := gx.Compile(gx.Html(gx.Body(
+		gx.Nav(gx.Text("nav")),
+		gx.Main(gx.Region("main", gx.H1(gx.Text("Title")), gx.Slot())),
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := compiled.Render(gx.Region("inner", gx.P(gx.Text("content"))))
+
+	if result := renderString(t, gx.NewContext(), gx.Partial("main", page)); result != `<h1>Title</h1><p>content</p>` {
+		t.Errorf("expected main region, got %q", result)
+	}
+	if result := renderString(t, gx.NewContext(), gx.Partial("inner", page)); result != `<p>content</p>` {
+		t.Errorf("expected inner region, got %q", result)
+	}
+}
+
+func TestPartialHTTP(t *testing.T) {
+	handler := gx.HandlerFunc(func(r *http.Request) gx.Node {
+		page := shopPage("apple")
+		if r.Header.Get("HX-Request") == "true" {
+			return gx.Partial("cart", page)
+		}
+		return page
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("HX-Request", "true")
+	handler.ServeHTTP(w, r)
+
+	if w.Body.String() != `<ul><li>apple (EUR)</li></ul>` {
+		t.Errorf("expected cart fragment, got %q", w.Body.String())
+	}
+}
+
+func TestPartialOfOptimizedTree(t *testing.T) {
+	page := gx.Optimize(gx.Div(gx.P(gx.Text("static")), gx.Region("static", gx.Span(gx.Text("region")))))
+
+	if result := renderString(t, gx.NewContext(), gx.Partial("static", page)); result != `<span>region</span>` {
+		t.Errorf("expected region, got %q", result)
+	}
+}
@@ -0,0 +1,229 @@
+package gx
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const renderBufferSize = 8 << 10
+
+var writerPool = sync.Pool{
+	New: func() any {
+		return &renderWriter{Writer: bufio.NewWriterSize(nil, renderBufferSize)}
+	},
+}
+
+// renderWriter buffers the output of Render and flushes it to an
+// http.Flusher when asked to.
+type renderWriter struct {
+	*bufio.Writer
+	out io.Writer
+}
+
+func (rw *renderWriter) Flush() error {
+	if err := rw.Writer.Flush(); err != nil {
+		return err
+	}
+	if f, ok := rw.out.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Render renders node to w through a buffer, so that nodes can write many
+// small pieces cheaply. The buffer is written to w when it is full, when a
+// Flush node is rendered and once node is rendered. When rendering fails, what
+// is still buffered is discarded.
+func Render(c *Context, w io.Writer, node Node) error {
+	rw := writerPool.Get().(*renderWriter)
+	rw.Reset(w)
+	rw.out = w
+	if c.layout != nil {
+		// The pooled writer may be the one of the previous render.
+		c.layout.w = nil
+	}
+	defer func() {
+		rw.Reset(nil)
+		rw.out = nil
+		writerPool.Put(rw)
+	}()
+
+	if err := node.Render(c, rw); err != nil {
+		return err
+	}
+	return rw.Writer.Flush()
+}
+
+// writeStrings writes each string in turn, avoiding the allocation of a
+// concatenated string when w is an io.StringWriter.
+func writeStrings(w io.Writer, strs ...string) error {
+	for _, s := range strs {
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type flushNode struct{}
+
+// Render flushes what is buffered by Render, and w itself when it is an
+// http.Flusher.
+func (f *flushNode) Render(c *Context, w io.Writer) error {
+	if c.compiler != nil {
+		return c.compiler.hole(c, f)
+	}
+	switch fw := w.(type) {
+	case interface{ Flush() error }:
+		return fw.Flush()
+	case http.Flusher:
+		fw.Flush()
+	}
+	return nil
+}
+
+// Flush sends what was rendered so far to the client, e.g. after the <head>
+// so that the browser can start fetching stylesheets while the body is
+// rendered.
+func Flush() Node {
+	return &flushNode{}
+}
+
+// RenderError is the error of a node that failed to render, with where it
+// happened. Elements and WithContext components wrap the errors of their
+// children in a RenderError.
+type RenderError struct {
+	// Path is the element in which the error occurred, as its ancestors and
+	// itself, e.g. "html > body > main > div#cart > ul > li[3]". Elements are
+	// described by their tag and ID, and their position among the elements
+	// with the same tag when there are several.
+	Path string
+	// Component is the name of the function of the innermost WithContext
+	// component the error occurred in, if any.
+	Component string
+	Err       error
+
+	// top is the outermost element of Path until its position is added by
+	// the element containing it.
+	top *Element
+}
+
+func (e *RenderError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Path)
+	if e.Component != "" {
+		if e.Path != "" {
+			b.WriteString(" ")
+		}
+		b.WriteString("(in ")
+		b.WriteString(e.Component)
+		b.WriteString(")")
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// wrapRenderError returns err with e added to its path. err itself is left
+// untouched since a node may return the same error each time it is rendered.
+// Partial stopping the rendering is not an error and is returned as is.
+func wrapRenderError(e *Element, err error) error {
+	if err == errRegionDone {
+		return err
+	}
+	re, ok := err.(*RenderError)
+	if !ok {
+		return &RenderError{Path: e.pathSegment(), Err: err, top: e}
+	}
+	wrapped := *re
+	wrapped.Path = e.pathSegment()
+	if re.Path != "" {
+		wrapped.Path += " > " + re.Path
+	}
+	wrapped.top = e
+	return &wrapped
+}
+
+// wrapRenderError returns err recording the component as the one it occurred
+// in, unless it occurred in a component within it.
+func (n *componentNode) wrapRenderError(err error) error {
+	if err == errRegionDone {
+		return err
+	}
+	re, ok := err.(*RenderError)
+	if !ok {
+		re = &RenderError{Err: err}
+	} else if re.Component != "" {
+		return err
+	} else {
+		wrapped := *re
+		re = &wrapped
+	}
+	re.Component = n.name()
+	return re
+}
+
+// indexRenderError returns err with the position of the child element that
+// failed added to its path, when e has several children with its tag.
+func (e *Element) indexRenderError(err error) error {
+	re, ok := err.(*RenderError)
+	if !ok || re.top == nil {
+		return err
+	}
+	failed := re.top
+	indexed := *re
+	indexed.top = nil
+
+	index, count := 0, 0
+	for child := range childElements(e.children) {
+		if child.tag != failed.tag {
+			continue
+		}
+		count++
+		if child == failed {
+			index = count
+		}
+	}
+	if index > 0 && count > 1 {
+		segment := failed.pathSegment()
+		indexed.Path = segment + "[" + strconv.Itoa(index) + "]" + re.Path[len(segment):]
+	}
+	return &indexed
+}
+
+// childElements returns the elements among nodes, including those in
+// fragments and chosen conditionals.
+func childElements(nodes []Node) iter.Seq[*Element] {
+	return func(yield func(*Element) bool) {
+		walkElements(nodes, yield)
+	}
+}
+
+func walkElements(nodes []Node, yield func(*Element) bool) bool {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *Element:
+			if !yield(n) {
+				return false
+			}
+		case *fragmentNode:
+			if !walkElements(n.children, yield) {
+				return false
+			}
+		case *ifNode:
+			if child := n.chosen(); child != nil && !walkElements([]Node{child}, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package gx_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestRender(t *testing.T) {
+	var buf strings.Builder
+
+	node := gx.Ul(gx.Repeat(1000, gx.Li(gx.Text("item"))))
+
+	if err := gx.Render(gx.NewContext(), &buf, node); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<ul>" + strings.Repeat("<li>item</li>", 1000) + "</ul>"
+	if buf.String() != expected {
+		t.Errorf("expected %d bytes, got %d", len(expected), buf.Len())
+	}
+}
+
+// flushRecorder records what was written to the response each time it is
+// flushed.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushed = append(f.flushed, f.Body.String())
+	f.ResponseRecorder.Flush()
+}
+
+func TestRenderFlush(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	node := gx.Html(
+		gx.Head(gx.CSSLink("/style.css")),
+		gx.Flush(),
+		gx.Body(gx.WithContext(func(c *gx.Context) gx.Node {
+			if w.Body.Len() == 0 {
+				t.Error("expected head to be written before the body is rendered")
+			}
+			return gx.P(gx.Text("body"))
+		})),
+	)
+
+	if err := gx.Render(gx.NewContext(), w, node); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFlush := `<html><head><link rel="stylesheet" href="/style.css"></head>`
+	if len(w.flushed) != 1 || w.flushed[0] != expectedFlush {
+		t.Errorf("expected one flush with %q, got %q", expectedFlush, w.flushed)
+	}
+	expected := expectedFlush + `<body><p>body</p></body></html>`
+	if w.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, w.Body.String())
+	}
+}
+
+func TestRenderFlushInCompiledTemplate(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	compiled, err := gx.Compile(gx.Html(gx.Head(), gx.Flush(), gx.Body(gx.Slot())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gx.Render(gx.NewContext(), w, compiled.Render(gx.Text("body"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(w.flushed) != 1 || w.flushed[0] != "<html><head></head>" {
+		t.Errorf("expected head to be flushed, got %q", w.flushed)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	var buf strings.Builder
+	errRender := errors.New("render failed")
+
+	err := gx.Render(gx.NewContext(), &buf, gx.Div(gx.Text("before"), failingNode{errRender}))
+	if !errors.Is(err, errRender) {
+		t.Fatalf("expected %v, got %v", errRender, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected buffered output to be discarded, got %q", buf.String())
+	}
+}
+
+var errOutOfStock = errors.New("out of stock")
+
+func cartItem(c *gx.Context) gx.Node {
+	item := gx.Use[string](c)
+	if item == "broken" {
+		return gx.Span(failingNode{errOutOfStock})
+	}
+	return gx.Text(item)
+}
+
+func TestRenderErrorPath(t *testing.T) {
+	items := []string{"apple", "pear", "broken"}
+	node := gx.Html(gx.Body(gx.Main(
+		gx.Div(gx.ID("cart"), gx.Ul(gx.Map(items, func(item string, _ int) gx.Node {
+			return gx.Li(gx.Provide(item, gx.WithContext(cartItem)))
+		}))),
+	)))
+
+	err := gx.Render(gx.NewContext(), io.Discard, node)
+	if !errors.Is(err, errOutOfStock) {
+		t.Fatalf("expected %v, got %v", errOutOfStock, err)
+	}
+	var renderErr *gx.RenderError
+	if !errors.As(err, &renderErr) {
+		t.Fatalf("expected a *gx.RenderError, got %T", err)
+	}
+	if expected := "html > body > main > div#cart > ul > li[3] > span"; renderErr.Path != expected {
+		t.Errorf("expected path %q, got %q", expected, renderErr.Path)
+	}
+	if expected := "github.com/bpingris/gx_test.cartItem"; renderErr.Component != expected {
+		t.Errorf("expected component %q, got %q", expected, renderErr.Component)
+	}
+	expected := "html > body > main > div#cart > ul > li[3] > span (in github.com/bpingris/gx_test.cartItem): out of stock"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestRenderErrorInComponent(t *testing.T) {
+	errRender := errors.New("render failed")
+	node := gx.WithContext(func(c *gx.Context) gx.Node {
+		return failingNode{errRender}
+	})
+
+	err := node.Render(gx.NewContext(), io.Discard)
+	var renderErr *gx.RenderError
+	if !errors.As(err, &renderErr) || renderErr.Err != errRender {
+		t.Fatalf("expected a *gx.RenderError wrapping %v, got %v", errRender, err)
+	}
+	if renderErr.Path != "" || !strings.HasPrefix(renderErr.Component, "github.com/bpingris/gx_test.TestRenderErrorInComponent.func") {
+		t.Errorf("unexpected path %q and component %q", renderErr.Path, renderErr.Component)
+	}
+}
+
+// memoNode renders its child once and then returns the same result.
+type memoNode struct {
+	child gx.Node
+	err   error
+	done  bool
+}
+
+func (m *memoNode) Render(c *gx.Context, w io.Writer) error {
+	if !m.done {
+		m.err, m.done = m.child.Render(c, w), true
+	}
+	return m.err
+}
+
+func TestRenderErrorReturnedAgain(t *testing.T) {
+	memo := &memoNode{child: gx.Div(failingNode{errors.New("boom")})}
+	node := gx.Section(gx.Div(memo), gx.Div())
+
+	for range 3 {
+		err := node.Render(gx.NewContext(), io.Discard)
+		if expected := "section > div[1] > div: boom"; err == nil || err.Error() != expected {
+			t.Fatalf("expected %q, got %v", expected, err)
+		}
+	}
+}
+
+func staticPage() gx.Node {
+	return gx.Fragment(
+		gx.DoctypeHTML5(),
+		gx.Html(
+			gx.Lang("en"),
+			gx.Head(
+				gx.UTF8Charset(),
+				gx.Title(gx.Text("Static page")),
+				gx.CSSLink("/style.css"),
+			),
+			gx.Body(
+				gx.Class("page"),
+				gx.Class("dark page"),
+				gx.Style_("margin: 0;"),
+				gx.Style_("padding: 0"),
+				gx.Nav(gx.Ul(
+					gx.Li(gx.A(gx.Href("/"), gx.Text("Home"))),
+					gx.Li(gx.A(gx.Href("/about?a=1&b=2"), gx.Text("About & more"))),
+				)),
+				gx.Main(
+					gx.ID("main"),
+					gx.H1(gx.Text("Hello <World>")),
+					gx.Input(gx.Type("text"), gx.Name("q"), gx.Required(), gx.Type("search")),
+					gx.Script(gx.Text(`if (a < b) { "</script>" }`)),
+					gx.Raw("<hr>"),
+				),
+			),
+		),
+	)
+}
+
+func TestRenderStaticTreeAllocations(t *testing.T) {
+	tree := staticPage()
+	ctx := gx.NewContext()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := gx.Render(ctx, io.Discard, tree); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkRenderStatic(b *testing.B) {
+	tree := staticPage()
+	ctx := gx.NewContext()
+	b.ReportAllocs()
+	for b.Loop() {
+		gx.Render(ctx, io.Discard, tree)
+	}
+}
+
+func BenchmarkRenderManyAttributes(b *testing.B) {
+	attrs := make(map[string]string, 40)
+	for i := range 40 {
+		attrs[fmt.Sprintf("data-attr-%d", i)] = fmt.Sprintf("value %d", i)
+	}
+	tree := gx.Div(gx.Class("a b"), gx.Attrs(attrs), gx.Class("b c"), gx.ID("many"))
+	ctx := gx.NewContext()
+	b.ReportAllocs()
+	for b.Loop() {
+		gx.Render(ctx, io.Discard, tree)
+	}
+}
+
+func BenchmarkRenderStaticToBuffer(b *testing.B) {
+	tree := staticPage()
+	ctx := gx.NewContext()
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for b.Loop() {
+		buf.Reset()
+		tree.Render(ctx, &buf)
+	}
+}
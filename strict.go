@@ -0,0 +1,110 @@
+package gx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrVoidContent = errors.New("gx: content in void element")
+	// ErrNotAttribute is returned for nodes other than attributes among
+	// attributes, and for attributes that elements do not find among their
+	// children.
+	ErrNotAttribute = errors.New("gx: not an attribute")
+)
+
+// WithStrict makes rendering fail on structural mistakes that are otherwise
+// silently ignored: content given to a void element such as <img> or <input>,
+// nodes other than attributes, such as a Slot, in an AttrGroup, and
+// attributes rendered as content, e.g. when returned by a WithContext
+// component.
+func WithStrict() ContextOption {
+	return func(c *Context) {
+		c.strict = true
+	}
+}
+
+// check returns an error describing the first structural mistake among the
+// children of the element.
+func (e *Element) check() error {
+	for _, child := range e.children {
+		if voidElements[e.tag] && isContent(child) {
+			return fmt.Errorf("%w <%s>: %s", ErrVoidContent, e.tag, describe(contentOf(child)))
+		}
+		if node := notAttr(child, false); node != nil {
+			return fmt.Errorf("%w in attributes of <%s>: %s", ErrNotAttribute, e.tag, describe(node))
+		}
+	}
+	return nil
+}
+
+// isContent reports whether node renders something else than attributes.
+func isContent(node Node) bool {
+	return contentOf(node) != nil
+}
+
+// contentOf returns the first node rendered by node that is not an
+// attribute, or nil.
+func contentOf(node Node) Node {
+	switch n := node.(type) {
+	case *attrNode, *attrGroupNode:
+		return nil
+	case *fragmentNode:
+		if i := slices.IndexFunc(n.children, isContent); i >= 0 {
+			return contentOf(n.children[i])
+		}
+		return nil
+	case *ifNode:
+		if child := n.chosen(); child != nil {
+			return contentOf(child)
+		}
+		return nil
+	}
+	return node
+}
+
+// notAttr returns the first node of an attribute group within node that is
+// not an attribute, or nil.
+func notAttr(node Node, inGroup bool) Node {
+	switch n := node.(type) {
+	case *attrNode:
+		return nil
+	case *attrGroupNode:
+		return firstNotAttr(n.attrs, true)
+	case *fragmentNode:
+		return firstNotAttr(n.children, inGroup)
+	case *ifNode:
+		if child := n.chosen(); child != nil {
+			return notAttr(child, inGroup)
+		}
+		return nil
+	}
+	if inGroup {
+		return node
+	}
+	return nil
+}
+
+func firstNotAttr(nodes []Node, inGroup bool) Node {
+	for _, node := range nodes {
+		if found := notAttr(node, inGroup); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func describe(node Node) string {
+	switch n := node.(type) {
+	case *Element:
+		return "<" + n.tag + ">"
+	case *textNode:
+		return fmt.Sprintf("text %q", n.text)
+	case *rawNode:
+		return fmt.Sprintf("raw HTML %q", n.text)
+	case *slotNode:
+		return fmt.Sprintf("slot %q", n.name)
+	}
+	return fmt.Sprintf("%T", node)
+}
@@ -0,0 +1,118 @@
+package gx_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestStrictErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    gx.Node
+		err     error
+		message string
+	}{
+		{
+			name:    "text in void element",
+			node:    gx.Div(gx.Img(gx.Src("/a.png"), gx.Text("caption"))),
+			err:     gx.ErrVoidContent,
+			message: `gx: content in void element <img>: text "caption"`,
+		},
+		{
+			name:    "element in void element",
+			node:    gx.Input(gx.Fragment(gx.Type("text"), gx.Span())),
+			err:     gx.ErrVoidContent,
+			message: `gx: content in void element <input>: <span>`,
+		},
+		{
+			name:    "element in attribute group",
+			node:    gx.Div(gx.AttrGroup(gx.ID("a"), gx.Span())),
+			err:     gx.ErrNotAttribute,
+			message: `gx: not an attribute in attributes of <div>: <span>`,
+		},
+		{
+			name:    "conditional text in attribute group",
+			node:    gx.Div(gx.AttrGroup(gx.If(true, gx.Text("a")))),
+			err:     gx.ErrNotAttribute,
+			message: `gx: not an attribute in attributes of <div>: text "a"`,
+		},
+		{
+			name: "attribute returned by a component",
+			node: gx.Div(gx.WithContext(func(c *gx.Context) gx.Node {
+				return gx.Class("x")
+			})),
+			err:     gx.ErrNotAttribute,
+			message: `gx: not an attribute: attribute "class" in the content of <div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.node.Render(gx.NewContext(gx.WithStrict()), io.Discard)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			var renderErr *gx.RenderError
+			if !errors.As(err, &renderErr) {
+				t.Fatalf("expected a *gx.RenderError, got %T", err)
+			}
+			if renderErr.Err.Error() != tt.message {
+				t.Errorf("expected %q, got %q", tt.message, renderErr.Err.Error())
+			}
+
+			if err := tt.node.Render(gx.NewContext(), io.Discard); err != nil {
+				t.Errorf("expected no error when not strict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStrictOptimized(t *testing.T) {
+	node := gx.Optimize(gx.Div(
+		gx.Img(gx.Text("caption")),
+		gx.WithContext(func(c *gx.Context) gx.Node {
+			return gx.Text("dynamic")
+		}),
+	))
+
+	if err := node.Render(gx.NewContext(gx.WithStrict()), io.Discard); !errors.Is(err, gx.ErrVoidContent) {
+		t.Errorf("expected %v, got %v", gx.ErrVoidContent, err)
+	}
+}
+
+func TestStrictValid(t *testing.T) {
+	node := gx.Fragment(
+		page(),
+		gx.Input(
+			gx.AttrGroup(gx.Type("checkbox"), gx.If(true, gx.Checked())),
+			gx.If(false, gx.Text("ignored")),
+			gx.Fragment(gx.Name("a")),
+		),
+		gx.Br(),
+	)
+
+	ctx := gx.NewContext(gx.WithStrict())
+	ctx.Push("John")
+	result := renderString(t, ctx, node)
+	if !strings.HasSuffix(result, `<input type="checkbox" checked name="a"><br>`) {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestStrictSlotInAttributes(t *testing.T) {
+	template := gx.Div(gx.AttrGroup(gx.Class("a"), gx.Slot()))
+
+	if _, err := gx.Compile(template, gx.WithStrict()); !errors.Is(err, gx.ErrNotAttribute) {
+		t.Fatalf("expected %v, got %v", gx.ErrNotAttribute, err)
+	} else if !strings.Contains(err.Error(), `slot "default"`) {
+		t.Errorf("expected the error to name the slot, got %q", err)
+	}
+
+	if _, err := gx.Compile(template); !errors.Is(err, gx.ErrMissingSlot) {
+		t.Errorf("expected %v, got %v", gx.ErrMissingSlot, err)
+	}
+}
@@ -0,0 +1,146 @@
+package gx
+
+import (
+	"slices"
+	"strings"
+)
+
+// unsafeURL replaces URLs rejected by the URLPolicy. It is inert when
+// followed by the browser and easy to spot in rendered output.
+const unsafeURL = "about:invalid#gx-unsafe-url"
+
+// SafeURL is a URL that is trusted by the caller and written to URL
+// attributes without being checked against the URLPolicy. It is still
+// escaped for the attribute it is written to.
+type SafeURL string
+
+// URLPolicy decides which URLs may be written into URL attributes such as
+// href, src and action. URLs it rejects are replaced by an inert URL.
+type URLPolicy struct {
+	// Schemes lists the allowed schemes, compared case-insensitively.
+	Schemes []string
+	// Relative allows URLs without a scheme, e.g. "/about" or "#top".
+	Relative bool
+}
+
+// DefaultURLPolicy is used by contexts created without WithURLPolicy.
+var DefaultURLPolicy = URLPolicy{
+	Schemes:  []string{"http", "https", "mailto", "tel"},
+	Relative: true,
+}
+
+func (p URLPolicy) equal(other URLPolicy) bool {
+	return p.Relative == other.Relative && slices.Equal(p.Schemes, other.Schemes)
+}
+
+// Allows reports whether url is accepted by the policy.
+func (p URLPolicy) Allows(url string) bool {
+	scheme, ok := urlScheme(url)
+	if !ok {
+		return false
+	}
+	if scheme == "" {
+		return p.Relative
+	}
+	for _, allowed := range p.Schemes {
+		if strings.EqualFold(scheme, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkedURLAttr reports whether the value of the attribute key is checked
+// against the URLPolicy. Attribute names are case-insensitive.
+func checkedURLAttr(key string) bool {
+	key = strings.ToLower(key)
+	return urlAttributes[key] || key == "srcset"
+}
+
+// allowsAttr reports whether the value of the URL attribute key is accepted
+// by the policy. A srcset is accepted when each of its candidate URLs is.
+func (p URLPolicy) allowsAttr(key, value string) bool {
+	if !strings.EqualFold(key, "srcset") {
+		return p.Allows(value)
+	}
+	for rest := value; ; {
+		var url string
+		url, rest = nextSrcsetURL(rest)
+		if url == "" {
+			return true
+		}
+		if !p.Allows(url) {
+			return false
+		}
+	}
+}
+
+// nextSrcsetURL returns the URL of the first image candidate of srcset and
+// the candidates that follow it. A candidate is a URL, which ends with
+// whitespace or a comma, followed by descriptors up to the next comma.
+func nextSrcsetURL(srcset string) (url, rest string) {
+	srcset = strings.TrimLeft(srcset, htmlSpace+",")
+	end := strings.IndexAny(srcset, htmlSpace)
+	if end < 0 {
+		end = len(srcset)
+	}
+	url, rest = srcset[:end], srcset[end:]
+	if trimmed := strings.TrimRight(url, ","); trimmed != url {
+		return trimmed, rest
+	}
+	if comma := strings.IndexByte(rest, ','); comma >= 0 {
+		return url, rest[comma+1:]
+	}
+	return url, ""
+}
+
+// urlScheme extracts the scheme of url the way a browser would, ignoring
+// leading control characters and embedded tabs and newlines. A URL without a
+// scheme yields "". It returns false when the text before the first colon is
+// not a valid scheme, since browsers disagree on how to handle those.
+func urlScheme(url string) (string, bool) {
+	url = strings.TrimLeftFunc(url, func(r rune) bool { return r <= ' ' })
+	end := strings.IndexAny(url, ":/?#")
+	if end < 0 || url[end] != ':' {
+		return "", true
+	}
+	scheme := url[:end]
+	if strings.ContainsAny(scheme, "\t\n\r") {
+		scheme = strings.Map(func(r rune) rune {
+			if r == '\t' || r == '\n' || r == '\r' {
+				return -1
+			}
+			return r
+		}, scheme)
+	}
+	for i := 0; i < len(scheme); i++ {
+		b := scheme[i]
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z':
+		case i > 0 && (('0' <= b && b <= '9') || b == '+' || b == '-' || b == '.'):
+		default:
+			return "", false
+		}
+	}
+	return scheme, scheme != ""
+}
+
+// SafeHref is like Href but skips the URLPolicy check.
+func SafeHref(url SafeURL) Node {
+	return &attrNode{key: "href", value: string(url), trusted: true}
+}
+
+// SafeSrc is like Src but skips the URLPolicy check.
+func SafeSrc(url SafeURL) Node {
+	return &attrNode{key: "src", value: string(url), trusted: true}
+}
+
+// SafeAction is like Action but skips the URLPolicy check.
+func SafeAction(url SafeURL) Node {
+	return &attrNode{key: "action", value: string(url), trusted: true}
+}
+
+// SafeURLAttr is like Attr for URL attributes but skips the URLPolicy check.
+func SafeURLAttr(attr string, url SafeURL) Node {
+	return &attrNode{key: attr, value: string(url), trusted: true}
+}
@@ -0,0 +1,165 @@
+package gx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestUnsafeURLsAreReplaced(t *testing.T) {
+	urls := []string{
+		"javascript:alert(1)",
+		"JavaScript:alert(1)",
+		" \tjavascript:alert(1)",
+		"java\tscript:alert(1)",
+		"data:text/html,<script>alert(1)</script>",
+		"vbscript:msgbox",
+		":no-scheme",
+	}
+
+	for _, url := range urls {
+		ctx := gx.NewContext()
+		var buf strings.Builder
+
+		gx.A(gx.Href(url)).Render(ctx, &buf)
+
+		expected := `<a href="about:invalid#gx-unsafe-url"></a>`
+		if buf.String() != expected {
+			t.Errorf("%q: expected '%q', got '%q'", url, expected, buf.String())
+		}
+	}
+}
+
+func TestURLAttributesAreCaseInsensitive(t *testing.T) {
+	nodes := []gx.Node{
+		gx.A(gx.Attr("HREF", "javascript:alert(1)")),
+		gx.A(gx.Attr("Href", "javascript:alert(1)")),
+		gx.A(gx.Attr("xlink:href", "javascript:alert(1)")),
+		gx.A(gx.Attr("XLINK:HREF", "javascript:alert(1)")),
+	}
+
+	for _, node := range nodes {
+		var buf strings.Builder
+		node.Render(gx.NewContext(), &buf)
+
+		if !strings.Contains(buf.String(), `="about:invalid#gx-unsafe-url"`) {
+			t.Errorf("expected the URL to be replaced, got '%q'", buf.String())
+		}
+	}
+
+	var buf strings.Builder
+	gx.A(gx.Attr("HREF", "/a b")).Render(gx.NewContext(), &buf)
+	if expected := `<a HREF="/a%20b"></a>`; buf.String() != expected {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestSrcset(t *testing.T) {
+	tests := []struct {
+		srcset   string
+		expected string
+	}{
+		{"/a.png 1x, /b.png 2x", "/a.png 1x, /b.png 2x"},
+		{"/a.png,/b.png 480w", "/a.png,/b.png 480w"},
+		{"https://example.com/a.png 100w, https://example.com/b.png 200w", "https://example.com/a.png 100w, https://example.com/b.png 200w"},
+		{"/a.png 1x, javascript:alert(1) 2x", "about:invalid#gx-unsafe-url"},
+		{"javascript:alert(1)", "about:invalid#gx-unsafe-url"},
+		{"/a.png 1x,javascript:alert(1)", "about:invalid#gx-unsafe-url"},
+	}
+
+	for _, tt := range tests {
+		var buf strings.Builder
+		gx.Img(gx.Attr("SRCSET", tt.srcset)).Render(gx.NewContext(), &buf)
+
+		expected := `<img SRCSET="` + tt.expected + `">`
+		if buf.String() != expected {
+			t.Errorf("%q: expected '%q', got '%q'", tt.srcset, expected, buf.String())
+		}
+	}
+}
+
+func TestSafeURLsAreKept(t *testing.T) {
+	urls := []string{
+		"https://example.com/a?b=c",
+		"http://example.com",
+		"mailto:john@example.com",
+		"/about",
+		"about",
+		"#top",
+		"?page=2",
+		"//example.com/a:b",
+	}
+
+	for _, url := range urls {
+		ctx := gx.NewContext()
+		var buf strings.Builder
+
+		gx.Img(gx.Src(url)).Render(ctx, &buf)
+
+		expected := `<img src="` + url + `">`
+		if buf.String() != expected {
+			t.Errorf("%q: expected '%q', got '%q'", url, expected, buf.String())
+		}
+	}
+}
+
+func TestURLPolicyIsApplied(t *testing.T) {
+	ctx := gx.NewContext(gx.WithURLPolicy(gx.URLPolicy{Schemes: []string{"https"}}))
+	var buf strings.Builder
+
+	node := gx.Div(
+		gx.A(gx.Href("https://example.com")),
+		gx.A(gx.Href("http://example.com")),
+		gx.A(gx.Href("/relative")),
+		gx.Form(gx.Action("mailto:john@example.com")),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<div>
+		<a href="https://example.com"></a>
+		<a href="about:invalid#gx-unsafe-url"></a>
+		<a href="about:invalid#gx-unsafe-url"></a>
+		<form action="about:invalid#gx-unsafe-url"></form>
+	</div>`
+	if buf.String() != normalizeHTML(expected) {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	ctx := gx.NewContext()
+	var buf strings.Builder
+
+	node := gx.Div(
+		gx.Img(gx.SafeSrc("data:image/png;base64,iVBORw0KGgo=")),
+		gx.A(gx.SafeHref(`javascript:void(0)`)),
+		gx.A(gx.Attr("href", "javascript:void(0)")),
+	)
+
+	node.Render(ctx, &buf)
+
+	expected := `<div>
+		<img src="data:image/png;base64,iVBORw0KGgo=">
+		<a href="javascript:void%280%29"></a>
+		<a href="about:invalid#gx-unsafe-url"></a>
+	</div>`
+	if buf.String() != normalizeHTML(expected) {
+		t.Errorf("expected '%q', got '%q'", expected, buf.String())
+	}
+}
+
+func TestURLPolicyAllows(t *testing.T) {
+	policy := gx.URLPolicy{Schemes: []string{"https"}, Relative: true}
+
+	if !policy.Allows("HTTPS://example.com") {
+		t.Error("expected scheme to be compared case-insensitively")
+	}
+	if policy.Allows("http://example.com") {
+		t.Error("expected scheme outside of the allowlist to be rejected")
+	}
+	if !policy.Allows("/path?next=https://example.com") {
+		t.Error("expected relative URL to be allowed")
+	}
+}
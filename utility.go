@@ -1,6 +1,9 @@
 package gx
 
-import "io"
+import (
+	"io"
+	"slices"
+)
 
 type ifNode struct {
 	condition  bool
@@ -10,15 +13,20 @@ type ifNode struct {
 
 // Render implements Node.
 func (in *ifNode) Render(c *Context, w io.Writer) error {
-	if in.condition && in.trueChild != nil {
-		return in.trueChild.Render(c, w)
-	}
-	if !in.condition && in.falseChild != nil {
-		return in.falseChild.Render(c, w)
+	if child := in.chosen(); child != nil {
+		return child.Render(c, w)
 	}
 	return nil
 }
 
+// chosen returns the child rendered for the condition, or nil.
+func (in *ifNode) chosen() Node {
+	if in.condition {
+		return in.trueChild
+	}
+	return in.falseChild
+}
+
 func If(condition bool, child Node) Node {
 	return &ifNode{condition, child, nil}
 }
@@ -53,8 +61,69 @@ func (m *mapNode[T]) Render(c *Context, w io.Writer) error {
 	return nil
 }
 
+func (m *mapNode[T]) expand() Node {
+	nodes := make([]Node, len(m.items))
+	for i, item := range m.items {
+		nodes[i] = m.fn(item, i)
+	}
+	return &fragmentNode{nodes}
+}
+
 func Map[T any](items []T, fn func(item T, index int) Node) Node { return &mapNode[T]{items, fn} }
 
+// expander is implemented by the Map nodes of any type.
+type expander interface {
+	expand() Node
+}
+
+// expandMaps returns children with the nodes of each Map built, so that the
+// attributes they return are found by the element, and whether there was
+// any. Other children are kept as is.
+func expandMaps(children []Node) ([]Node, bool) {
+	if !slices.ContainsFunc(children, containsMap) {
+		return children, false
+	}
+	expanded := make([]Node, len(children))
+	for i, child := range children {
+		expanded[i] = expandMap(child)
+	}
+	return expanded, true
+}
+
+func containsMap(node Node) bool {
+	switch n := node.(type) {
+	case expander:
+		return true
+	case *fragmentNode:
+		return slices.ContainsFunc(n.children, containsMap)
+	case *attrGroupNode:
+		return slices.ContainsFunc(n.attrs, containsMap)
+	case *ifNode:
+		return n.chosen() != nil && containsMap(n.chosen())
+	}
+	return false
+}
+
+func expandMap(node Node) Node {
+	switch n := node.(type) {
+	case expander:
+		return expandMap(n.expand())
+	case *fragmentNode:
+		if children, expanded := expandMaps(n.children); expanded {
+			return &fragmentNode{children}
+		}
+	case *attrGroupNode:
+		if attrs, expanded := expandMaps(n.attrs); expanded {
+			return &attrGroupNode{attrs}
+		}
+	case *ifNode:
+		if child := n.chosen(); child != nil && containsMap(child) {
+			return expandMap(child)
+		}
+	}
+	return node
+}
+
 var _ Node = (*mapNode[any])(nil)
 
 type repeatNode struct {
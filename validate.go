@@ -0,0 +1,168 @@
+package gx
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// observer is notified of the elements and text rendered.
+type observer interface {
+	open(e *Element)
+	close(e *Element)
+	text(s string)
+}
+
+// observe renders node with o notified of what is rendered.
+func observe(c *Context, node Node, o observer) error {
+	parent := c.observer
+	c.observer = o
+	err := node.Render(c, io.Discard)
+	c.observer = parent
+	return err
+}
+
+// elementPath is the stack of elements being rendered.
+type elementPath []*Element
+
+// String returns the path as the elements separated by ">", each described
+// by its tag and ID, e.g. "html > body > div#cart > ul > li".
+func (p elementPath) String() string {
+	var b strings.Builder
+	for i, e := range p {
+		if i > 0 {
+			b.WriteString(" > ")
+		}
+		b.WriteString(e.pathSegment())
+	}
+	return b.String()
+}
+
+// pathSegment describes the element in a path by its tag and ID.
+func (e *Element) pathSegment() string {
+	if id, ok := e.attr("id"); ok && id != "" {
+		return e.tag + "#" + id
+	}
+	return e.tag
+}
+
+// parent returns the tag of the element containing the current one.
+func (p elementPath) parent() string {
+	if len(p) < 2 {
+		return ""
+	}
+	return p[len(p)-2].tag
+}
+
+// inside reports whether an element containing the current one is a tag.
+func (p elementPath) inside(tag string) bool {
+	for _, e := range p[:max(len(p)-1, 0)] {
+		if e.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation is a place where a node tree breaks the content model of HTML,
+// which browsers repair by changing the structure of the page.
+type Violation struct {
+	// Path is the element where the violation occurs, as its ancestors and
+	// itself, e.g. "html > body > ul#list > li".
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate renders node with c and returns the violations of the HTML content
+// model found: list items outside of lists, nested forms, blocks inside
+// paragraphs, table rows outside of table sections, duplicate IDs and the
+// like. It is meant for tests and development. The HTML pre-rendered by
+// Compile is not validated, validate the template before compiling it.
+func Validate(c *Context, node Node) ([]Violation, error) {
+	v := &validator{ids: make(map[string]string)}
+	if err := observe(c, node, v); err != nil {
+		return nil, err
+	}
+	return v.violations, nil
+}
+
+// allowedParents lists the elements some elements must be directly in.
+var allowedParents = map[string]map[string]bool{
+	"li":       tags("ul", "ol", "menu"),
+	"dt":       tags("dl", "div"),
+	"dd":       tags("dl", "div"),
+	"tr":       tags("thead", "tbody", "tfoot"),
+	"td":       tags("tr"),
+	"th":       tags("tr"),
+	"thead":    tags("table"),
+	"tbody":    tags("table"),
+	"tfoot":    tags("table"),
+	"caption":  tags("table"),
+	"option":   tags("select", "datalist", "optgroup"),
+	"optgroup": tags("select"),
+	"summary":  tags("details"),
+}
+
+// notNested lists the elements that cannot contain themselves.
+var notNested = tags("a", "button", "form", "label")
+
+type validator struct {
+	path elementPath
+	// ids maps the IDs seen so far to the path of their element.
+	ids        map[string]string
+	violations []Violation
+}
+
+func (v *validator) report(format string, args ...any) {
+	v.violations = append(v.violations, Violation{v.path.String(), fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) open(e *Element) {
+	v.path = append(v.path, e)
+
+	// The elements rendered at the root may be given the right parent by
+	// the page they are part of.
+	if parent := v.path.parent(); parent != "" {
+		if parents, ok := allowedParents[e.tag]; ok && !parents[parent] {
+			v.report("<%s> must be in %s, not in <%s>", e.tag, tagList(parents), parent)
+		}
+	}
+	if notNested[e.tag] && v.path.inside(e.tag) {
+		v.report("<%s> cannot be nested in another <%s>", e.tag, e.tag)
+	}
+	if paragraphClosers[e.tag] && v.path.inside("p") {
+		v.report("<%s> cannot be in a <p>, which browsers close before it", e.tag)
+	}
+	if id, ok := e.attr("id"); ok && id != "" {
+		if first, seen := v.ids[id]; seen {
+			v.report("duplicate id %q, first used by %s", id, first)
+		} else {
+			v.ids[id] = v.path.String()
+		}
+	}
+}
+
+// tagList returns the tags of set in alphabetical order, e.g.
+// "<menu>, <ol> or <ul>".
+func tagList(set map[string]bool) string {
+	names := slices.Sorted(maps.Keys(set))
+	for i := range names {
+		names[i] = "<" + names[i] + ">"
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+func (v *validator) close(e *Element) {
+	v.path = v.path[:len(v.path)-1]
+}
+
+func (v *validator) text(s string) {}
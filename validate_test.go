@@ -0,0 +1,72 @@
+package gx_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bpingris/gx"
+)
+
+func TestValidate(t *testing.T) {
+	node := gx.Html(
+		gx.Body(
+			gx.Main(
+				gx.ID("main"),
+				gx.Div(gx.Li(gx.Text("orphan"))),
+				gx.Form(gx.ID("outer"), gx.Div(gx.Form())),
+				gx.P(gx.Span(gx.Div(gx.Text("block")))),
+				gx.Table(gx.Tr(gx.Td(gx.Text("cell")))),
+				gx.Ul(gx.ID("main"), gx.Li(gx.Text("item"))),
+			),
+		),
+	)
+
+	violations, err := gx.Validate(gx.NewContext(), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`html > body > main#main > div > li: <li> must be in <menu>, <ol> or <ul>, not in <div>`,
+		`html > body > main#main > form#outer > div > form: <form> cannot be nested in another <form>`,
+		`html > body > main#main > p > span > div: <div> cannot be in a <p>, which browsers close before it`,
+		`html > body > main#main > table > tr: <tr> must be in <tbody>, <tfoot> or <thead>, not in <table>`,
+		`html > body > main#main > ul#main: duplicate id "main", first used by html > body > main#main`,
+	}
+	var result []string
+	for _, v := range violations {
+		result = append(result, v.String())
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	ctx := gx.NewContext()
+	ctx.Push("John")
+
+	for _, node := range []gx.Node{page(), gx.Optimize(page()), gx.Li(gx.Text("component"))} {
+		violations, err := gx.Validate(ctx, node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(violations) != 0 {
+			t.Errorf("expected no violations, got %v", violations)
+		}
+	}
+}
+
+func TestValidateOptimized(t *testing.T) {
+	node := gx.Optimize(gx.Div(gx.P(gx.Div()), gx.WithContext(func(c *gx.Context) gx.Node {
+		return gx.Text("dynamic")
+	})))
+
+	violations, err := gx.Validate(gx.NewContext(), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Path != "div > p > div" {
+		t.Errorf("expected a violation at div > p > div, got %v", violations)
+	}
+}